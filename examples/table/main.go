@@ -58,9 +58,9 @@ func main() {
 
 	// Set columns
 	table.SetColumns([]widgets.Column{
-		{Title: "ID", Width: 4, Alignment: widgets.AlignRight},
+		{Title: "ID", Width: 4, Type: widgets.ColumnInt},
 		{Title: "Name", Width: 20, Alignment: widgets.AlignLeft},
-		{Title: "Age", Width: 5, Alignment: widgets.AlignRight},
+		{Title: "Age", Width: 5, Type: widgets.ColumnInt},
 		{Title: "City", Width: 15, Alignment: widgets.AlignLeft},
 	})
 
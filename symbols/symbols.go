@@ -53,6 +53,15 @@ const (
 	Block7     = '▇' // Lower seven eighths block
 	BlockFull  = '█' // Full block
 
+	// Left partial blocks for horizontal sub-cell drawing
+	BlockLeft1 = '▏' // Left one eighth block
+	BlockLeft2 = '▎' // Left one quarter block
+	BlockLeft3 = '▍' // Left three eighths block
+	BlockLeft4 = '▌' // Left half block
+	BlockLeft5 = '▋' // Left five eighths block
+	BlockLeft6 = '▊' // Left three quarters block
+	BlockLeft7 = '▉' // Left seven eighths block
+
 	// TTY patterns for graph drawing
 	TTYStart = ' '
 	TTY1     = '_'
@@ -81,6 +90,15 @@ const (
 	Left       = '←'
 	Right      = '→'
 	Enter      = '↵'
+
+	// Sort direction indicators
+	TriangleUp   = '▲'
+	TriangleDown = '▼'
+)
+
+// Selection symbols
+const (
+	Check = '✓'
 )
 
 // BraillePatterns returns all braille patterns in order
@@ -109,6 +127,19 @@ var BlockPatterns = []string{
 	string(BlockFull),
 }
 
+// BlockLeftPatterns returns all left partial block patterns in order
+var BlockLeftPatterns = []string{
+	string(BlockStart),
+	string(BlockLeft1),
+	string(BlockLeft2),
+	string(BlockLeft3),
+	string(BlockLeft4),
+	string(BlockLeft5),
+	string(BlockLeft6),
+	string(BlockLeft7),
+	string(BlockFull),
+}
+
 // TTYPatterns returns all TTY patterns in order
 var TTYPatterns = []string{
 	string(TTYStart),
@@ -122,5 +153,17 @@ var TTYPatterns = []string{
 	string(TTYFull),
 }
 
+// SpinnerBraille is a braille dot spinner animation
+var SpinnerBraille = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// SpinnerBrailleWide is a spinner that walks a gap around a full braille cell
+var SpinnerBrailleWide = []string{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"}
+
+// SpinnerLine is a spinner made of ASCII line characters
+var SpinnerLine = []string{"|", "/", "-", "\\"}
+
+// SpinnerBlock is a spinner made of quadrant block characters
+var SpinnerBlock = []string{"▖", "▘", "▝", "▗"}
+
 // SuperScript numbers
 var SuperScript = []string{"⁰", "¹", "²", "³", "⁴", "⁵", "⁶", "⁷", "⁸", "⁹"}
@@ -90,6 +90,9 @@ func TestGraphSymbols(t *testing.T) {
 		{"Block6", Block6, '▆'},
 		{"Block7", Block7, '▇'},
 		{"BlockFull", BlockFull, '█'},
+		{"BlockLeft1", BlockLeft1, '▏'},
+		{"BlockLeft4", BlockLeft4, '▌'},
+		{"BlockLeft7", BlockLeft7, '▉'},
 	}
 
 	for _, tt := range blockTests {
@@ -136,3 +139,25 @@ func TestSymbolConsistency(t *testing.T) {
 		t.Error("BoxDrawingRoundBottomRight and RoundRightDown should be the same")
 	}
 }
+
+func TestSpinnerFrames(t *testing.T) {
+	t.Parallel()
+
+	spinners := map[string][]string{
+		"SpinnerBraille":     SpinnerBraille,
+		"SpinnerBrailleWide": SpinnerBrailleWide,
+		"SpinnerLine":        SpinnerLine,
+		"SpinnerBlock":       SpinnerBlock,
+	}
+
+	for name, frames := range spinners {
+		if len(frames) < 2 {
+			t.Errorf("%s should have at least two frames, got %d", name, len(frames))
+		}
+		for i, frame := range frames {
+			if len([]rune(frame)) != 1 {
+				t.Errorf("%s frame %d should be a single character, got %q", name, i, frame)
+			}
+		}
+	}
+}
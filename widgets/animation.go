@@ -0,0 +1,258 @@
+package widgets
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Easing maps animation progress (0-1) to eased progress
+type Easing func(t float64) float64
+
+// EaseLinear moves at a constant speed
+func EaseLinear(t float64) float64 {
+	return t
+}
+
+// EaseOut starts fast and slows down towards the target
+func EaseOut(t float64) float64 {
+	return 1 - (1-t)*(1-t)*(1-t)
+}
+
+// EaseSpring overshoots the target slightly and settles back onto it
+func EaseSpring(t float64) float64 {
+	if t >= 1 {
+		return 1
+	}
+	return 1 - math.Exp(-6*t)*math.Cos(12*t)
+}
+
+// DefaultFrameInterval is the redraw interval used while animations run
+const DefaultFrameInterval = 33 * time.Millisecond
+
+// AnimationClock drives animated widgets. It calls OnFrame at a fixed
+// interval while at least one animation is running and stops afterwards,
+// so idle widgets don't cause redraws. A single clock can be shared by
+// any number of widgets.
+type AnimationClock struct {
+	FrameInterval time.Duration
+	OnFrame       func()
+
+	mu       sync.Mutex
+	deadline time.Time
+	running  bool
+	now      func() time.Time
+}
+
+// NewAnimationClock creates a clock that calls onFrame for every frame
+func NewAnimationClock(onFrame func()) *AnimationClock {
+	return &AnimationClock{
+		FrameInterval: DefaultFrameInterval,
+		OnFrame:       onFrame,
+		now:           time.Now,
+	}
+}
+
+// NewScreenAnimationClock creates a clock that posts an interrupt event to
+// the screen for every frame, waking up the event loop to redraw
+func NewScreenAnimationClock(screen tcell.Screen) *AnimationClock {
+	return NewAnimationClock(func() {
+		_ = screen.PostEvent(tcell.NewEventInterrupt(nil))
+	})
+}
+
+// Now returns the clock's current time
+func (c *AnimationClock) Now() time.Time {
+	if c == nil || c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
+// Animating returns whether any animation is still running
+func (c *AnimationClock) Animating() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.running
+}
+
+// schedule keeps the clock running for at least the given duration
+func (c *AnimationClock) schedule(duration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if deadline := c.Now().Add(duration); deadline.After(c.deadline) {
+		c.deadline = deadline
+	}
+	if c.running {
+		return
+	}
+	c.running = true
+	go c.run()
+}
+
+// run emits frames until the deadline has passed
+func (c *AnimationClock) run() {
+	interval := c.FrameInterval
+	if interval <= 0 {
+		interval = DefaultFrameInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		c.mu.Lock()
+		done := !c.Now().Before(c.deadline)
+		if done {
+			c.running = false
+		}
+		c.mu.Unlock()
+
+		// The last frame is still emitted so the final value gets drawn
+		if c.OnFrame != nil {
+			c.OnFrame()
+		}
+		if done {
+			return
+		}
+	}
+}
+
+// Animation configures how a widget transitions between values
+type Animation struct {
+	Clock    *AnimationClock
+	Duration time.Duration
+	Easing   Easing
+}
+
+// NewAnimation creates an animation configuration. A nil easing uses
+// EaseOut.
+func NewAnimation(clock *AnimationClock, duration time.Duration, easing Easing) *Animation {
+	if easing == nil {
+		easing = EaseOut
+	}
+	return &Animation{
+		Clock:    clock,
+		Duration: duration,
+		Easing:   easing,
+	}
+}
+
+// now returns the current time of the animation's clock
+func (a *Animation) now() time.Time {
+	if a == nil {
+		return time.Now()
+	}
+	return a.Clock.Now()
+}
+
+// tween interpolates between two values over time. It refers to its
+// animation rather than holding the easing func, which keeps structs that
+// embed it comparable.
+type tween struct {
+	from, to  float64
+	start     time.Time
+	duration  time.Duration
+	animation *Animation
+}
+
+// value returns the interpolated value at the given time
+func (tw tween) value(now time.Time) float64 {
+	if tw.duration <= 0 {
+		return tw.to
+	}
+	progress := float64(now.Sub(tw.start)) / float64(tw.duration)
+	if progress >= 1 {
+		return tw.to
+	}
+	if progress < 0 {
+		progress = 0
+	}
+	easing := Easing(EaseLinear)
+	if tw.animation != nil && tw.animation.Easing != nil {
+		easing = tw.animation.Easing
+	}
+	return tw.from + (tw.to-tw.from)*easing(progress)
+}
+
+// settle returns the tween if it ends at value. Otherwise the value was
+// changed without animating, and a tween resting at value is returned.
+func (tw tween) settle(value float64) tween {
+	if tw.to == value {
+		return tw
+	}
+	return tween{from: value, to: value}
+}
+
+// start returns a tween from the currently displayed value to target.
+// Without an animation the tween jumps straight to the target.
+func (a *Animation) start(current tween, target float64) tween {
+	if a == nil || a.Duration <= 0 {
+		return tween{from: target, to: target}
+	}
+	now := a.now()
+	next := tween{
+		from:      current.value(now),
+		to:        target,
+		start:     now,
+		duration:  a.Duration,
+		animation: a,
+	}
+	if a.Clock != nil {
+		a.Clock.schedule(a.Duration)
+	}
+	return next
+}
+
+// animatedValue is a value between 0 and 1 that tweens towards new values
+// when an animation is set. Meter and Gauge embed it for their value,
+// formatter and animation.
+type animatedValue struct {
+	Value float64
+
+	// Formatter controls how the value is displayed
+	Formatter ValueFormatter
+
+	// Animation, when set, makes SetValue tween towards the new value
+	Animation *Animation
+	tween     tween
+}
+
+// SetValue sets the value (0-1), clamping values outside that range
+func (v *animatedValue) SetValue(value float64) {
+	if value < 0 {
+		value = 0
+	}
+	if value > 1 {
+		value = 1
+	}
+	v.tween = v.Animation.start(v.tween.settle(v.Value), value)
+	v.Value = value
+}
+
+// SetAnimation enables animated transitions for SetValue.
+// Passing nil makes the value snap to new values.
+func (v *animatedValue) SetAnimation(animation *Animation) {
+	v.Animation = animation
+}
+
+// DisplayValue returns the value currently shown, which trails Value
+// while an animation is running
+func (v *animatedValue) DisplayValue() float64 {
+	return v.tween.settle(v.Value).value(v.Animation.now())
+}
+
+// SetFormatter sets the formatter used to display the value
+func (v *animatedValue) SetFormatter(formatter ValueFormatter) {
+	v.Formatter = formatter
+}
+
+// FormatValue returns the display text for the currently shown value
+func (v *animatedValue) FormatValue() string {
+	if v.Formatter == nil {
+		return PercentFormatter(v.DisplayValue())
+	}
+	return v.Formatter(v.DisplayValue())
+}
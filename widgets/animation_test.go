@@ -0,0 +1,155 @@
+package widgets
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// newTestClock returns a clock whose time is controlled by the test
+func newTestClock(now *time.Time) *AnimationClock {
+	clock := NewAnimationClock(nil)
+	clock.now = func() time.Time { return *now }
+	return clock
+}
+
+func TestEasingEndpoints(t *testing.T) {
+	t.Parallel()
+
+	easings := map[string]Easing{
+		"linear": EaseLinear,
+		"out":    EaseOut,
+		"spring": EaseSpring,
+	}
+
+	for name, easing := range easings {
+		if got := easing(0); math.Abs(got) > 1e-9 {
+			t.Errorf("%s(0) = %f, want 0", name, got)
+		}
+		if got := easing(1); math.Abs(got-1) > 1e-9 {
+			t.Errorf("%s(1) = %f, want 1", name, got)
+		}
+	}
+
+	if EaseOut(0.5) <= EaseLinear(0.5) {
+		t.Error("EaseOut should be ahead of linear at the midpoint")
+	}
+}
+
+func TestMeterAnimation(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(0, 0)
+	clock := newTestClock(&now)
+	clock.FrameInterval = time.Hour // keep the frame goroutine idle
+
+	meter := NewMeter(screen, 0, 0, 50)
+	meter.SetValue(0.1)
+	meter.SetAnimation(NewAnimation(clock, time.Second, EaseLinear))
+	meter.SetValue(0.9)
+
+	if meter.Value != 0.9 {
+		t.Errorf("Value should be the target immediately, got %f", meter.Value)
+	}
+	if got := meter.DisplayValue(); math.Abs(got-0.1) > 1e-9 {
+		t.Errorf("Expected display value 0.1 at start, got %f", got)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if got := meter.DisplayValue(); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("Expected display value 0.5 halfway, got %f", got)
+	}
+
+	// Retargeting starts from the value currently shown
+	meter.SetValue(0.3)
+	if got := meter.DisplayValue(); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("Expected retargeted animation to start at 0.5, got %f", got)
+	}
+
+	now = now.Add(time.Second)
+	if got := meter.DisplayValue(); got != 0.3 {
+		t.Errorf("Expected display value to settle at 0.3, got %f", got)
+	}
+	if !clock.Animating() {
+		t.Error("Clock should report running until its next frame")
+	}
+}
+
+func TestMeterWithoutAnimation(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	meter := NewMeter(screen, 0, 0, 50)
+	meter.SetValue(0.7)
+	if got := meter.DisplayValue(); got != 0.7 {
+		t.Errorf("Expected meter to snap to 0.7, got %f", got)
+	}
+
+	// Writing the field directly also snaps
+	meter.Value = 0.2
+	if got := meter.DisplayValue(); got != 0.2 {
+		t.Errorf("Expected meter to show 0.2, got %f", got)
+	}
+}
+
+func TestMultiMeterAnimation(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(0, 0)
+	clock := newTestClock(&now)
+	clock.FrameInterval = time.Hour
+
+	mm := NewMultiMeter(screen, 0, 0, 20, 10)
+	mm.AddItem(MeterItem{Label: "CPU", Value: 10, MaxValue: 100})
+	mm.SetAnimation(NewAnimation(clock, time.Second, EaseLinear))
+	mm.UpdateMeter("CPU", 90)
+
+	now = now.Add(250 * time.Millisecond)
+	if got := mm.displayValue(mm.Items[0]); math.Abs(got-30) > 1e-9 {
+		t.Errorf("Expected display value 30 a quarter of the way, got %f", got)
+	}
+
+	now = now.Add(time.Second)
+	if got := mm.displayValue(mm.Items[0]); got != 90 {
+		t.Errorf("Expected display value to settle at 90, got %f", got)
+	}
+}
+
+func TestAnimationClockStops(t *testing.T) {
+	t.Parallel()
+
+	frames := make(chan struct{}, 100)
+	clock := NewAnimationClock(func() { frames <- struct{}{} })
+	clock.FrameInterval = time.Millisecond
+
+	animation := NewAnimation(clock, 10*time.Millisecond, nil)
+	animation.start(tween{}, 1)
+
+	received := 0
+	deadline := time.After(time.Second)
+	for clock.Animating() {
+		select {
+		case <-frames:
+			received++
+		case <-deadline:
+			t.Fatal("Clock did not stop after the animation finished")
+		}
+	}
+	received += len(frames)
+	if received == 0 {
+		t.Error("Expected at least one frame while animating")
+	}
+}
@@ -0,0 +1,75 @@
+package widgets
+
+import (
+	"github.com/deadjoe/termdodo/symbols"
+	"github.com/gdamore/tcell/v2"
+)
+
+// brailleDots maps a dot position within a cell (column, row) to its bit
+var brailleDots = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+// brailleCanvas is an off-screen grid of braille dots, two dots wide and
+// four dots high per cell, with one color per cell
+type brailleCanvas struct {
+	width, height int
+	cells         []rune
+	colors        []tcell.Color
+}
+
+// newBrailleCanvas creates a canvas covering width x height cells
+func newBrailleCanvas(width, height int) *brailleCanvas {
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+	return &brailleCanvas{
+		width:  width,
+		height: height,
+		cells:  make([]rune, width*height),
+		colors: make([]tcell.Color, width*height),
+	}
+}
+
+// dotWidth returns the canvas width in dots
+func (c *brailleCanvas) dotWidth() int {
+	return c.width * 2
+}
+
+// dotHeight returns the canvas height in dots
+func (c *brailleCanvas) dotHeight() int {
+	return c.height * 4
+}
+
+// set turns on the dot at (x, y) and colors its cell.
+// Dots outside the canvas are ignored.
+func (c *brailleCanvas) set(x, y int, color tcell.Color) {
+	if x < 0 || y < 0 || x >= c.dotWidth() || y >= c.dotHeight() {
+		return
+	}
+	i := (y/4)*c.width + x/2
+	c.cells[i] |= brailleDots[x%2][y%4]
+	c.colors[i] = color
+}
+
+// draw copies the canvas onto the screen at (x, y). Empty cells are left
+// untouched so text drawn beforehand stays visible.
+func (c *brailleCanvas) draw(screen tcell.Screen, x, y int, style tcell.Style) {
+	for row := 0; row < c.height; row++ {
+		for col := 0; col < c.width; col++ {
+			i := row*c.width + col
+			if c.cells[i] == 0 {
+				continue
+			}
+			cellStyle := style
+			if c.colors[i] != tcell.ColorDefault {
+				cellStyle = style.Foreground(c.colors[i])
+			}
+			screen.SetContent(x+col, y+row, symbols.BrailleStart+c.cells[i], nil, cellStyle)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package widgets
+
+import (
+	"io/fs"
+	"path"
+
+	"github.com/deadjoe/termdodo/draw"
+	"github.com/deadjoe/termdodo/theme"
+	"github.com/gdamore/tcell/v2"
+)
+
+// FilePickerMode is what a FilePicker lets the user choose
+type FilePickerMode int
+
+// File picker modes
+const (
+	// PickFile chooses a file; Enter on a directory expands or collapses it
+	PickFile FilePickerMode = iota
+	// PickDir chooses a directory and hides files
+	PickDir
+)
+
+// FilePicker is a dialog for choosing a file or directory from an fs.FS.
+// Enter chooses the selected entry and Esc cancels; Ctrl+T toggles hidden
+// files and Ctrl+S cycles the sort order. Other keys go to the tree, where
+// typing jumps to entries starting with what was typed.
+type FilePicker struct {
+	X, Y          int
+	Width, Height int
+	Screen        tcell.Screen
+	Style         tcell.Style
+	PathStyle     tcell.Style
+	Title         string
+	Mode          FilePickerMode
+
+	Files *FileTree
+	Tree  *TreeView
+
+	// OnSelect is called with the chosen path
+	OnSelect func(path string)
+	// OnCancel is called when the dialog is dismissed without a choice
+	OnCancel func()
+
+	chosen string
+	done   bool
+}
+
+// NewFilePicker creates a picker showing root of fsys
+func NewFilePicker(screen tcell.Screen, fsys fs.FS, root string, mode FilePickerMode, x, y, width, height int) *FilePicker {
+	p := &FilePicker{
+		X:         x,
+		Y:         y,
+		Width:     width,
+		Height:    height,
+		Screen:    screen,
+		Style:     theme.Current.GetStyle(),
+		PathStyle: theme.Current.GetAccentStyle(),
+		Title:     "Open",
+		Mode:      mode,
+	}
+	if mode == PickDir {
+		p.Title = "Choose Directory"
+	}
+	p.Tree = NewTreeView(screen, x+1, y+1, width-2, height-3)
+	p.Tree.TypeAhead = true
+	p.Files = &FileTree{Tree: p.Tree, FS: fsys, Root: path.Clean(root), DirsFirst: true, DirsOnly: mode == PickDir}
+	p.Files.Refresh()
+	return p
+}
+
+// Chosen returns the chosen path, and false while nothing was chosen
+func (p *FilePicker) Chosen() (string, bool) {
+	return p.chosen, p.done && p.chosen != ""
+}
+
+// Done returns whether the user chose a path or cancelled
+func (p *FilePicker) Done() bool {
+	return p.done
+}
+
+// Draw draws the dialog
+func (p *FilePicker) Draw() {
+	for y := p.Y; y < p.Y+p.Height; y++ {
+		for x := p.X; x < p.X+p.Width; x++ {
+			p.Screen.SetContent(x, y, ' ', nil, p.Style)
+		}
+	}
+	box := draw.NewBox(p.Screen, p.X, p.Y, p.Width, p.Height)
+	box.SetStyle(p.Style)
+	box.SetTitle(p.Title)
+	box.SetRound(true)
+	box.Draw()
+
+	p.Tree.X, p.Tree.Y = box.InnerX(), box.InnerY()
+	p.Tree.Width, p.Tree.Height = box.InnerWidth(), box.InnerHeight()-1
+	p.Tree.Draw()
+
+	// The selected path on the last line, clipped from the left
+	if entry := p.Files.SelectedEntry(); entry != nil {
+		runes := []rune(entry.Path)
+		if width := box.InnerWidth(); len(runes) > width && width > 0 {
+			runes = runes[len(runes)-width:]
+		}
+		for i, r := range runes {
+			p.Screen.SetContent(box.InnerX()+i, box.InnerY()+box.InnerHeight()-1, r, nil, p.PathStyle)
+		}
+	}
+}
+
+// HandleEvent handles keyboard events
+func (p *FilePicker) HandleEvent(ev *tcell.EventKey) bool {
+	if p.done {
+		return false
+	}
+	// Keys go to the filter prompt while it is open, so Esc and Enter
+	// close it instead of the dialog
+	if p.Tree.Prompting() {
+		return p.Tree.HandleKeyEvent(ev)
+	}
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		p.done = true
+		if p.OnCancel != nil {
+			p.OnCancel()
+		}
+		return true
+	case tcell.KeyEnter:
+		entry := p.Files.SelectedEntry()
+		if entry == nil {
+			return false
+		}
+		if entry.IsDir && p.Mode == PickFile {
+			return p.Tree.ToggleSelected()
+		}
+		p.chosen = entry.Path
+		p.done = true
+		if p.OnSelect != nil {
+			p.OnSelect(entry.Path)
+		}
+		return true
+	case tcell.KeyCtrlT:
+		p.Files.SetShowHidden(!p.Files.ShowHidden)
+		return true
+	case tcell.KeyCtrlS:
+		p.Files.SetSort((p.Files.SortBy+1)%(SortByModTime+1), p.Files.DirsFirst)
+		return true
+	}
+	return p.Tree.HandleKeyEvent(ev)
+}
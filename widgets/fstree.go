@@ -0,0 +1,275 @@
+package widgets
+
+import (
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FileSortOrder is the order of the entries in a directory
+type FileSortOrder int
+
+// File sort orders
+const (
+	// SortByName orders entries by name, ignoring case
+	SortByName FileSortOrder = iota
+	// SortBySize orders entries by size, largest first
+	SortBySize
+	// SortByModTime orders entries by modification time, newest first
+	SortByModTime
+)
+
+// FileEntry is the file or directory a FileTree node stands for. It is
+// stored in the node's Data.
+type FileEntry struct {
+	Path    string // slash-separated path within the file system
+	Name    string
+	IsDir   bool
+	Size    int64
+	ModTime time.Time
+}
+
+// FileTree fills a TreeView from an fs.FS, e.g. os.DirFS("/") or an
+// fstest.MapFS. Directories are read when they are first expanded.
+type FileTree struct {
+	Tree *TreeView
+	FS   fs.FS
+	Root string // directory shown as the root node
+
+	ShowHidden bool // show names starting with a dot
+	DirsFirst  bool // list directories before files
+	SortBy     FileSortOrder
+	// Patterns, when set, hide files whose name matches none of them, see
+	// path.Match. Directories are always shown.
+	Patterns []string
+	// DirsOnly hides files
+	DirsOnly bool
+}
+
+// NewFileTree shows root of fsys in tree, with directories first and
+// sorted by name
+func NewFileTree(tree *TreeView, fsys fs.FS, root string) *FileTree {
+	ft := &FileTree{
+		Tree:      tree,
+		FS:        fsys,
+		Root:      path.Clean(root),
+		DirsFirst: true,
+	}
+	ft.Refresh()
+	return ft
+}
+
+// Refresh rereads the tree, keeping expanded directories and the
+// selection where they still exist
+func (ft *FileTree) Refresh() {
+	var expanded []string
+	var walk func(node *TreeNode)
+	walk = func(node *TreeNode) {
+		if entry := FileEntryOf(node); entry != nil && node.Expanded {
+			expanded = append(expanded, entry.Path)
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	if ft.Tree.Root != nil {
+		walk(ft.Tree.Root)
+	}
+	var selected string
+	if entry := FileEntryOf(ft.Tree.Selected); entry != nil {
+		selected = entry.Path
+	}
+
+	ft.Root = path.Clean(ft.Root)
+	root := &TreeNode{
+		Text:   ft.Root,
+		Data:   &FileEntry{Path: ft.Root, Name: path.Base(ft.Root), IsDir: true},
+		Loader: ft.load,
+		Style:  ft.Tree.Style,
+	}
+	ft.Tree.SetRoot(root)
+	ft.Tree.Selected = root
+	if len(expanded) == 0 {
+		ft.Tree.setExpanded(root, true)
+	}
+	for _, p := range expanded {
+		if node := ft.find(p); node != nil {
+			ft.Tree.setExpanded(node, true)
+		}
+	}
+	if selected != "" {
+		ft.SelectPath(selected)
+	}
+}
+
+// SetShowHidden sets whether to show names starting with a dot
+func (ft *FileTree) SetShowHidden(show bool) {
+	ft.ShowHidden = show
+	ft.Refresh()
+}
+
+// SetSort sets the order of the entries in each directory
+func (ft *FileTree) SetSort(order FileSortOrder, dirsFirst bool) {
+	ft.SortBy = order
+	ft.DirsFirst = dirsFirst
+	ft.Refresh()
+}
+
+// SetPatterns shows only files matching one of the glob patterns, e.g.
+// "*.go". Passing no patterns shows all files.
+func (ft *FileTree) SetPatterns(patterns ...string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return err
+		}
+	}
+	ft.Patterns = patterns
+	ft.Refresh()
+	return nil
+}
+
+// SelectedEntry returns the entry of the selected node, or nil
+func (ft *FileTree) SelectedEntry() *FileEntry {
+	return FileEntryOf(ft.Tree.Selected)
+}
+
+// FileEntryOf returns the entry a FileTree node stands for, or nil
+func FileEntryOf(node *TreeNode) *FileEntry {
+	if node == nil {
+		return nil
+	}
+	entry, _ := node.Data.(*FileEntry)
+	return entry
+}
+
+// SelectPath expands the directories leading to p and selects it. It
+// returns false if p isn't shown.
+func (ft *FileTree) SelectPath(p string) bool {
+	node := ft.find(p)
+	if node == nil {
+		return false
+	}
+	ft.Tree.Selected = node
+	ft.Tree.EnsureVisible()
+	return true
+}
+
+// find returns the node for p, reading the directories leading to it
+func (ft *FileTree) find(p string) *TreeNode {
+	p = path.Clean(p)
+	node := ft.Tree.Root
+	if node == nil || p == ft.Root {
+		return node
+	}
+	rel := p
+	if ft.Root != "." {
+		if !strings.HasPrefix(p, ft.Root+"/") {
+			return nil
+		}
+		rel = strings.TrimPrefix(p, ft.Root+"/")
+	}
+
+	for _, name := range strings.Split(rel, "/") {
+		ft.Tree.LoadChildren(node)
+		var next *TreeNode
+		for _, child := range node.Children {
+			if entry := FileEntryOf(child); entry != nil && entry.Name == name {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// load reads the directory of a node
+func (ft *FileTree) load(node *TreeNode, done func([]*TreeNode, error)) {
+	dir := FileEntryOf(node)
+	if dir == nil {
+		done(nil, nil)
+		return
+	}
+	entries, err := fs.ReadDir(ft.FS, dir.Path)
+	if err != nil {
+		done(nil, err)
+		return
+	}
+
+	files := make([]*FileEntry, 0, len(entries))
+	for _, e := range entries {
+		if !ft.shown(e) {
+			continue
+		}
+		entry := &FileEntry{Path: path.Join(dir.Path, e.Name()), Name: e.Name(), IsDir: e.IsDir()}
+		if info, err := e.Info(); err == nil {
+			entry.Size = info.Size()
+			entry.ModTime = info.ModTime()
+		}
+		files = append(files, entry)
+	}
+	ft.sort(files)
+
+	children := make([]*TreeNode, len(files))
+	for i, entry := range files {
+		child := &TreeNode{Text: entry.Name, Data: entry}
+		if entry.IsDir {
+			child.Text += "/"
+			child.Loader = ft.load
+		}
+		children[i] = child
+	}
+	done(children, nil)
+}
+
+// shown returns whether an entry passes the hidden, pattern and
+// directory filters
+func (ft *FileTree) shown(e fs.DirEntry) bool {
+	if !ft.ShowHidden && strings.HasPrefix(e.Name(), ".") {
+		return false
+	}
+	if e.IsDir() {
+		return true
+	}
+	if ft.DirsOnly {
+		return false
+	}
+	if len(ft.Patterns) == 0 {
+		return true
+	}
+	for _, pattern := range ft.Patterns {
+		if ok, _ := path.Match(pattern, e.Name()); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sort orders the entries of a directory
+func (ft *FileTree) sort(files []*FileEntry) {
+	sort.SliceStable(files, func(i, j int) bool {
+		a, b := files[i], files[j]
+		if ft.DirsFirst && a.IsDir != b.IsDir {
+			return a.IsDir
+		}
+		switch ft.SortBy {
+		case SortBySize:
+			if a.Size != b.Size {
+				return a.Size > b.Size
+			}
+		case SortByModTime:
+			if !a.ModTime.Equal(b.ModTime) {
+				return a.ModTime.After(b.ModTime)
+			}
+		}
+		if la, lb := strings.ToLower(a.Name), strings.ToLower(b.Name); la != lb {
+			return la < lb
+		}
+		return a.Name < b.Name
+	})
+}
@@ -0,0 +1,205 @@
+package widgets
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func testFS() fstest.MapFS {
+	now := time.Now()
+	return fstest.MapFS{
+		"src/main.go":      {Data: []byte("package main"), ModTime: now},
+		"src/util.go":      {Data: []byte("package main\n\nfunc util() {}"), ModTime: now.Add(-time.Hour)},
+		"src/lib/lib.go":   {Data: []byte("package lib")},
+		"README.md":        {Data: []byte("# readme, the largest file here")},
+		"Makefile":         {Data: []byte("all:")},
+		".git/config":      {Data: []byte("[core]")},
+		".gitignore":       {Data: []byte("bin")},
+		"docs/guide.md":    {Data: []byte("guide")},
+		"docs/img/a.png":   {Data: []byte("png")},
+		"docs/img/b.jpeg":  {Data: []byte("jpeg")},
+		"empty/.keep":      {},
+		"src/lib/.hidden":  {},
+		"src/lib/lib_test": {},
+	}
+}
+
+func childNamed(node *TreeNode, text string) *TreeNode {
+	for _, child := range node.Children {
+		if child.Text == text {
+			return child
+		}
+	}
+	return nil
+}
+
+func childNames(node *TreeNode) []string {
+	var names []string
+	for _, child := range node.Children {
+		names = append(names, child.Text)
+	}
+	return names
+}
+
+func TestFileTree(t *testing.T) {
+	t.Parallel()
+	tree := NewTreeView(createTestScreen(t), 0, 0, 40, 20)
+	files := NewFileTree(tree, testFS(), ".")
+
+	if got := childNames(tree.Root); !equalStrings(got, []string{"docs/", "empty/", "src/", "Makefile", "README.md"}) {
+		t.Errorf("Expected directories first and hidden files skipped, got %v", got)
+	}
+	src := tree.Root.Children[2]
+	if len(src.Children) != 0 || !src.hasChildren() {
+		t.Error("Expected directories to be read on first expand")
+	}
+
+	if !files.SelectPath("src/lib/lib.go") {
+		t.Fatal("Expected to find src/lib/lib.go")
+	}
+	if entry := files.SelectedEntry(); entry.Path != "src/lib/lib.go" || entry.IsDir || entry.Size != 11 {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	if !src.Expanded || !src.Children[0].Expanded {
+		t.Error("Expected the parents of the selected file expanded")
+	}
+
+	// Refreshing keeps expanded directories and the selection
+	files.SetShowHidden(true)
+	if got := childNames(tree.Root); !equalStrings(got, []string{".git/", "docs/", "empty/", "src/", ".gitignore", "Makefile", "README.md"}) {
+		t.Errorf("Expected hidden files shown, got %v", got)
+	}
+	if entry := files.SelectedEntry(); entry == nil || entry.Path != "src/lib/lib.go" {
+		t.Errorf("Expected the selection kept, got %+v", entry)
+	}
+
+	files.SetSort(SortBySize, false)
+	if got := childNames(childNamed(tree.Root, "src/")); !equalStrings(got, []string{"util.go", "main.go", "lib/"}) {
+		t.Errorf("Expected src sorted by size, got %v", got)
+	}
+	files.SetSort(SortByModTime, true)
+	if got := childNames(childNamed(tree.Root, "src/")); !equalStrings(got, []string{"lib/", "main.go", "util.go"}) {
+		t.Errorf("Expected src sorted by time, got %v", got)
+	}
+
+	if err := files.SetPatterns("*.md", "*.png"); err != nil {
+		t.Fatal(err)
+	}
+	files.SelectPath("docs/img/a.png")
+	docs := childNamed(tree.Root, "docs/")
+	if got := childNames(docs); !equalStrings(got, []string{"img/", "guide.md"}) {
+		t.Errorf("Expected docs filtered, got %v", got)
+	}
+	if got := childNames(docs.Children[0]); !equalStrings(got, []string{"a.png"}) {
+		t.Errorf("Expected docs/img filtered, got %v", got)
+	}
+	if err := files.SetPatterns("[a-"); err == nil {
+		t.Error("Expected an invalid pattern to be rejected")
+	}
+}
+
+func TestFileTreeReadError(t *testing.T) {
+	t.Parallel()
+	tree := NewTreeView(createTestScreen(t), 0, 0, 40, 20)
+	NewFileTree(tree, testFS(), "missing")
+	if tree.Root.LoadError() == nil {
+		t.Error("Expected reading a missing directory to fail")
+	}
+}
+
+func TestFilePicker(t *testing.T) {
+	t.Parallel()
+	screen := createTestScreen(t)
+	screen.SetSize(40, 12)
+	key := func(p *FilePicker, k tcell.Key, r rune) {
+		p.HandleEvent(tcell.NewEventKey(k, r, tcell.ModNone))
+	}
+
+	var chosen string
+	picker := NewFilePicker(screen, testFS(), ".", PickFile, 0, 0, 40, 12)
+	picker.OnSelect = func(path string) { chosen = path }
+
+	for _, r := range "src" {
+		key(picker, tcell.KeyRune, r)
+	}
+	key(picker, tcell.KeyEnter, 0) // expands src
+	if _, ok := picker.Chosen(); ok || !picker.Tree.Selected.Expanded {
+		t.Fatal("Expected Enter to expand a directory in file mode")
+	}
+	key(picker, tcell.KeyRight, 0)
+	key(picker, tcell.KeyRune, 'm')
+	picker.Draw()
+	if got := readRow(screen, 1, 10, 11); got != "src/main.go" {
+		t.Errorf("Expected the selected path shown, got %q", got)
+	}
+	key(picker, tcell.KeyEnter, 0)
+	if path, ok := picker.Chosen(); !ok || path != "src/main.go" || chosen != path {
+		t.Errorf("Expected src/main.go chosen, got %q", path)
+	}
+
+	dirs := NewFilePicker(screen, testFS(), ".", PickDir, 0, 0, 40, 12)
+	if got := childNames(dirs.Tree.Root); !equalStrings(got, []string{"docs/", "empty/", "src/"}) {
+		t.Errorf("Expected only directories, got %v", got)
+	}
+	key(dirs, tcell.KeyCtrlT, 0)
+	if !dirs.Files.ShowHidden || len(dirs.Tree.Root.Children) != 4 {
+		t.Error("Expected Ctrl+T to show hidden directories")
+	}
+	key(dirs, tcell.KeyDown, 0)
+	key(dirs, tcell.KeyEnter, 0)
+	if path, ok := dirs.Chosen(); !ok || path != ".git" {
+		t.Errorf("Expected .git chosen, got %q", path)
+	}
+
+	// Roots are cleaned like NewFileTree's
+	sub := NewFilePicker(screen, testFS(), "src/", PickFile, 0, 0, 40, 12)
+	if sub.Files.Root != "src" || len(sub.Tree.Root.Children) != 3 {
+		t.Errorf("Expected the src/ root cleaned, got %q with %v", sub.Files.Root, childNames(sub.Tree.Root))
+	}
+	if !sub.Files.SelectPath("src/main.go") {
+		t.Error("Expected paths below a cleaned root to be found")
+	}
+
+	cancelled := NewFilePicker(screen, testFS(), ".", PickFile, 0, 0, 40, 12)
+	key(cancelled, tcell.KeyEscape, 0)
+	if _, ok := cancelled.Chosen(); ok || !cancelled.Done() {
+		t.Error("Expected Esc to cancel")
+	}
+}
+
+func TestFilePickerFilterPrompt(t *testing.T) {
+	t.Parallel()
+	screen := createTestScreen(t)
+	screen.SetSize(40, 12)
+	picker := NewFilePicker(screen, testFS(), ".", PickFile, 0, 0, 40, 12)
+	key := func(k tcell.Key, r rune) {
+		picker.HandleEvent(tcell.NewEventKey(k, r, tcell.ModNone))
+	}
+
+	key(tcell.KeyRune, '/')
+	for _, r := range "read" {
+		key(tcell.KeyRune, r)
+	}
+	key(tcell.KeyEscape, 0)
+	if picker.Done() || picker.Tree.Prompting() || picker.Tree.Filtered() {
+		t.Error("Expected Esc to close the prompt and clear the filter, not the dialog")
+	}
+
+	key(tcell.KeyRune, '/')
+	for _, r := range "read" {
+		key(tcell.KeyRune, r)
+	}
+	key(tcell.KeyEnter, 0)
+	if picker.Done() || !picker.Tree.Filtered() {
+		t.Error("Expected Enter to keep the filter, not choose")
+	}
+
+	// Refreshing keeps filtering the rebuilt tree
+	key(tcell.KeyCtrlT, 0)
+	if got := visibleTexts(picker.Tree); !equalStrings(got, []string{".", "README.md"}) {
+		t.Errorf("Expected the refreshed tree filtered, got %v", got)
+	}
+}
@@ -0,0 +1,225 @@
+package widgets
+
+import (
+	"math"
+	"strings"
+
+	"github.com/deadjoe/termdodo/theme"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Threshold colors a value once it reaches the given level
+type Threshold struct {
+	Value float64
+	Color tcell.Color
+}
+
+// Gauge arc geometry, in degrees measured counter-clockwise from 3 o'clock
+const (
+	gaugeStartAngle = 210.0
+	gaugeSweep      = 240.0
+)
+
+// Gauge represents a speedometer-style arc gauge drawn with braille dots.
+// SetValue moves the needle, tweening the arc when SetAnimation is used.
+type Gauge struct {
+	X, Y          int
+	Width, Height int
+	Screen        tcell.Screen
+	Style         tcell.Style
+	TrackStyle    tcell.Style
+	LabelStyle    tcell.Style
+
+	animatedValue
+	Label     string
+	MinLabel  string
+	MaxLabel  string
+	ShowValue bool
+
+	UseGradient    bool
+	GradientColors []string
+	Thresholds     []Threshold
+}
+
+// NewGauge creates a new gauge widget
+func NewGauge(screen tcell.Screen, x, y, width, height int) *Gauge {
+	return &Gauge{
+		X:              x,
+		Y:              y,
+		Width:          width,
+		Height:         height,
+		Screen:         screen,
+		Style:          theme.Current.GetStyle(),
+		TrackStyle:     theme.Current.GetStyle().Dim(true),
+		LabelStyle:     theme.Current.GetStyle(),
+		MinLabel:       "0",
+		MaxLabel:       "100",
+		ShowValue:      true,
+		animatedValue:  animatedValue{Formatter: PercentFormatter},
+		GradientColors: theme.Current.Meter,
+	}
+}
+
+// SetLabel sets the label drawn under the gauge
+func (g *Gauge) SetLabel(label string) {
+	g.Label = label
+}
+
+// SetRangeLabels sets the labels drawn at the min and max tick marks
+func (g *Gauge) SetRangeLabels(min, max string) {
+	g.MinLabel = min
+	g.MaxLabel = max
+}
+
+// SetShowValue sets whether to show the value in the center of the gauge
+func (g *Gauge) SetShowValue(show bool) {
+	g.ShowValue = show
+}
+
+// SetGradient colors the arc along its length using the given colors.
+// An empty list uses the theme's meter colors.
+func (g *Gauge) SetGradient(colors []string) {
+	if len(colors) == 0 {
+		colors = theme.Current.Meter
+	}
+	g.GradientColors = colors
+	g.UseGradient = true
+}
+
+// SetThresholds colors the whole arc by the highest threshold the value
+// has reached. Thresholds take precedence over the gradient.
+func (g *Gauge) SetThresholds(thresholds []Threshold) {
+	g.Thresholds = thresholds
+}
+
+// SetStyle sets the style for the filled part of the arc
+func (g *Gauge) SetStyle(style tcell.Style) {
+	g.Style = style
+}
+
+// SetTrackStyle sets the style for the unfilled part of the arc
+func (g *Gauge) SetTrackStyle(style tcell.Style) {
+	g.TrackStyle = style
+}
+
+// thresholdColor returns the color of the highest threshold reached by
+// value
+func (g *Gauge) thresholdColor(value float64) (tcell.Color, bool) {
+	color, found, level := tcell.ColorDefault, false, math.Inf(-1)
+	for _, th := range g.Thresholds {
+		if value >= th.Value && th.Value >= level {
+			color, found, level = th.Color, true, th.Value
+		}
+	}
+	return color, found
+}
+
+// fillColor returns the color of the filled arc at the given position (0-1)
+// when the gauge shows value
+func (g *Gauge) fillColor(value, position float64) tcell.Color {
+	if color, ok := g.thresholdColor(value); ok {
+		return color
+	}
+	if g.UseGradient && len(g.GradientColors) > 0 {
+		fg, _, _ := theme.GetGradientStyle(g.GradientColors, position).Decompose()
+		return fg
+	}
+	fg, _, _ := g.Style.Decompose()
+	return fg
+}
+
+// arcRows returns the number of rows used by the arc itself
+func (g *Gauge) arcRows() int {
+	if g.Height > 1 {
+		return g.Height - 1
+	}
+	return g.Height
+}
+
+// Draw draws the gauge
+func (g *Gauge) Draw() {
+	if g.Width <= 0 || g.Height <= 0 {
+		return
+	}
+
+	value := g.DisplayValue()
+	canvas := newBrailleCanvas(g.Width, g.arcRows())
+
+	// Fit the arc inside the canvas. The arc ends sit below the center by
+	// half the radius, since sin(30°) = 0.5.
+	radius := float64(canvas.dotWidth()-1) / 2
+	if r := float64(canvas.dotHeight()-1) / 1.5; r < radius {
+		radius = r
+	}
+	if radius < 1 {
+		return
+	}
+	cx := float64(canvas.dotWidth()-1) / 2
+	cy := radius
+
+	trackColor, _, _ := g.TrackStyle.Decompose()
+	steps := int(gaugeSweep/180*math.Pi*radius*2) + 1
+	for i := 0; i <= steps; i++ {
+		position := float64(i) / float64(steps)
+		color := trackColor
+		if position <= value && value > 0 {
+			color = g.fillColor(value, position)
+		}
+		angle := (gaugeStartAngle - position*gaugeSweep) * math.Pi / 180
+		for _, r := range []float64{radius, radius - 1} {
+			x := cx + r*math.Cos(angle)
+			y := cy - r*math.Sin(angle)
+			canvas.set(int(math.Round(x)), int(math.Round(y)), color)
+		}
+	}
+
+	// Tick marks point inwards from both ends of the arc
+	for _, position := range []float64{0, 1} {
+		angle := (gaugeStartAngle - position*gaugeSweep) * math.Pi / 180
+		for r := radius - 2; r >= radius-3 && r > 0; r-- {
+			x := cx + r*math.Cos(angle)
+			y := cy - r*math.Sin(angle)
+			canvas.set(int(math.Round(x)), int(math.Round(y)), trackColor)
+		}
+	}
+
+	canvas.draw(g.Screen, g.X, g.Y, g.Style)
+
+	if g.ShowValue {
+		row := int(cy) / 4
+		g.drawCentered(g.Y+row, strings.TrimSpace(g.FormatValue()), g.LabelStyle)
+	}
+
+	// Range labels line up under the ends of the arc
+	if g.Height > 1 {
+		y := g.Y + g.Height - 1
+		offset := radius * math.Cos(30*math.Pi/180)
+		minCol := int(math.Round(cx-offset)) / 2
+		maxCol := int(math.Round(cx+offset)) / 2
+		g.drawText(g.X+minCol, y, g.MinLabel)
+		g.drawText(g.X+maxCol+1-len([]rune(g.MaxLabel)), y, g.MaxLabel)
+		g.drawCentered(y, g.Label, g.LabelStyle)
+	}
+}
+
+// drawText draws a string clipped to the gauge bounds
+func (g *Gauge) drawText(x, y int, text string) {
+	for i, r := range []rune(text) {
+		if x+i < g.X || x+i >= g.X+g.Width {
+			continue
+		}
+		g.Screen.SetContent(x+i, y, r, nil, g.LabelStyle)
+	}
+}
+
+// drawCentered draws a string centered horizontally within the gauge
+func (g *Gauge) drawCentered(y int, text string, style tcell.Style) {
+	runes := []rune(text)
+	if len(runes) > g.Width {
+		runes = runes[:g.Width]
+	}
+	x := g.X + (g.Width-len(runes))/2
+	for i, r := range runes {
+		g.Screen.SetContent(x+i, y, r, nil, style)
+	}
+}
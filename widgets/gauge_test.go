@@ -0,0 +1,129 @@
+package widgets
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestNewGauge(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGauge(screen, 0, 0, 24, 8)
+	if g == nil {
+		t.Fatal("Expected non-nil gauge")
+	}
+	if !g.ShowValue {
+		t.Error("ShowValue should be true by default")
+	}
+	if g.FormatValue() != PercentFormatter(0) {
+		t.Errorf("Expected default percent formatting, got %q", g.FormatValue())
+	}
+}
+
+func TestGaugeSetValue(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGauge(screen, 0, 0, 24, 8)
+
+	testCases := []struct {
+		value    float64
+		expected float64
+	}{
+		{0.5, 0.5},
+		{-0.1, 0.0}, // Should clamp to 0
+		{1.1, 1.0},  // Should clamp to 1
+	}
+
+	for _, tc := range testCases {
+		g.SetValue(tc.value)
+		if g.Value != tc.expected {
+			t.Errorf("SetValue(%f): expected %f, got %f", tc.value, tc.expected, g.Value)
+		}
+	}
+}
+
+func TestGaugeThresholds(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGauge(screen, 0, 0, 24, 8)
+	g.SetThresholds([]Threshold{
+		{Value: 0, Color: tcell.ColorGreen},
+		{Value: 0.9, Color: tcell.ColorRed},
+		{Value: 0.7, Color: tcell.ColorYellow},
+	})
+
+	testCases := []struct {
+		value    float64
+		expected tcell.Color
+	}{
+		{0.2, tcell.ColorGreen},
+		{0.75, tcell.ColorYellow},
+		{0.95, tcell.ColorRed},
+	}
+
+	for _, tc := range testCases {
+		g.SetValue(tc.value)
+		if got := g.fillColor(g.Value, 0); got != tc.expected {
+			t.Errorf("Value %f: expected color %v, got %v", tc.value, tc.expected, got)
+		}
+	}
+}
+
+func TestGaugeDraw(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(40, 10)
+
+	g := NewGauge(screen, 0, 0, 24, 8)
+	g.SetValue(0.5)
+	g.SetLabel("CPU")
+	g.SetFormatter(func(value float64) string { return "half" })
+	g.Draw()
+
+	braille := 0
+	for y := 0; y < 7; y++ {
+		for x := 0; x < 24; x++ {
+			r, _, _, _ := screen.GetContent(x, y)
+			if r > 0x2800 && r <= 0x28FF {
+				braille++
+			}
+		}
+	}
+	if braille == 0 {
+		t.Error("Expected the arc to be drawn with braille characters")
+	}
+
+	if text := readRow(screen, 0, 4, 24); !strings.Contains(text, "half") {
+		t.Errorf("Expected formatted value in the gauge center, got %q", text)
+	}
+	if text := readRow(screen, 0, 7, 24); !strings.Contains(text, "CPU") || !strings.Contains(text, "100") {
+		t.Errorf("Expected label and range labels on the last row, got %q", text)
+	}
+}
+
+// readRow returns the text of a screen row
+func readRow(screen tcell.Screen, x, y, width int) string {
+	runes := make([]rune, 0, width)
+	for i := 0; i < width; i++ {
+		r, _, _, _ := screen.GetContent(x+i, y)
+		runes = append(runes, r)
+	}
+	return string(runes)
+}
@@ -0,0 +1,406 @@
+package widgets
+
+import (
+	"github.com/deadjoe/termdodo/theme"
+	"github.com/gdamore/tcell/v2"
+)
+
+// HeatmapCell represents a single value in the heatmap
+type HeatmapCell struct {
+	Key   string
+	Label string
+	Value float64
+}
+
+// defaultHeatmapColors is used when the theme has no graph gradient
+var defaultHeatmapColors = []string{"#5ccc96", "#e6c84c", "#e05d5d"}
+
+// Heatmap represents a grid of colored cells, one per value. Without labels
+// each screen row holds two cells using half blocks.
+type Heatmap struct {
+	X, Y          int
+	Width, Height int
+	Screen        tcell.Screen
+	Style         tcell.Style
+	SelectedStyle tcell.Style
+
+	Cells          []HeatmapCell
+	MinValue       float64
+	MaxValue       float64
+	GradientColors []string
+	ShowLabels     bool
+	MaxCellWidth   int
+
+	Selected int
+	Hovered  int
+	OnSelect func(cell HeatmapCell)
+	OnHover  func(cell HeatmapCell)
+
+	pressed bool // the left button was down at the last mouse event
+}
+
+// NewHeatmap creates a new heatmap widget
+func NewHeatmap(screen tcell.Screen, x, y, width, height int) *Heatmap {
+	colors := theme.Current.Graph
+	if len(colors) == 0 {
+		colors = defaultHeatmapColors
+	}
+	return &Heatmap{
+		X:              x,
+		Y:              y,
+		Width:          width,
+		Height:         height,
+		Screen:         screen,
+		Style:          theme.Current.GetStyle(),
+		SelectedStyle:  theme.Current.GetStyle().Foreground(theme.Current.Selected),
+		Cells:          make([]HeatmapCell, 0),
+		MinValue:       0,
+		MaxValue:       100,
+		GradientColors: colors,
+		MaxCellWidth:   8,
+		Selected:       -1,
+		Hovered:        -1,
+	}
+}
+
+// SetCells sets the heatmap cells
+func (h *Heatmap) SetCells(cells []HeatmapCell) {
+	h.Cells = cells
+	if h.Selected >= len(h.Cells) {
+		h.Selected = len(h.Cells) - 1
+	}
+	if h.Hovered >= len(h.Cells) {
+		h.Hovered = -1
+	}
+}
+
+// AddCell adds a cell to the end of the heatmap
+func (h *Heatmap) AddCell(cell HeatmapCell) {
+	h.Cells = append(h.Cells, cell)
+}
+
+// ClearCells removes all cells
+func (h *Heatmap) ClearCells() {
+	h.Cells = nil
+	h.Selected = -1
+	h.Hovered = -1
+}
+
+// UpdateCell updates the value of the cell with the given key
+func (h *Heatmap) UpdateCell(key string, value float64) {
+	for i := range h.Cells {
+		if h.Cells[i].Key == key {
+			h.Cells[i].Value = value
+			return
+		}
+	}
+}
+
+// SetRange sets the values mapped to the start and end of the gradient
+func (h *Heatmap) SetRange(min, max float64) {
+	h.MinValue = min
+	h.MaxValue = max
+}
+
+// SetGradient sets the colors cells are shaded with, from low to high
+func (h *Heatmap) SetGradient(colors []string) {
+	if len(colors) == 0 {
+		colors = defaultHeatmapColors
+	}
+	h.GradientColors = colors
+}
+
+// SetShowLabels sets whether to draw cell labels. Labelled cells take a
+// full screen row each.
+func (h *Heatmap) SetShowLabels(show bool) {
+	h.ShowLabels = show
+}
+
+// SetStyle sets the default style for the heatmap
+func (h *Heatmap) SetStyle(style tcell.Style) {
+	h.Style = style
+}
+
+// cellsPerRow returns the number of cells stacked in one screen row
+func (h *Heatmap) cellsPerRow() int {
+	if h.ShowLabels {
+		return 1
+	}
+	return 2
+}
+
+// layout returns the number of grid columns and the width of each cell.
+// It picks the widest cells that still fit every value in the widget.
+func (h *Heatmap) layout() (columns, cellWidth int) {
+	if h.Width <= 0 || len(h.Cells) == 0 {
+		return 0, 0
+	}
+
+	capacity := h.Height * h.cellsPerRow()
+	maxWidth := h.MaxCellWidth
+	if maxWidth <= 0 || maxWidth > h.Width {
+		maxWidth = h.Width
+	}
+	for cellWidth = maxWidth; cellWidth > 1; cellWidth-- {
+		columns = h.Width / cellWidth
+		rows := (len(h.Cells) + columns - 1) / columns
+		if rows <= capacity {
+			return columns, cellWidth
+		}
+	}
+	return h.Width, 1
+}
+
+// cellColor returns the gradient color for a value
+func (h *Heatmap) cellColor(value float64) tcell.Color {
+	position := 0.0
+	if h.MaxValue > h.MinValue {
+		position = (value - h.MinValue) / (h.MaxValue - h.MinValue)
+	}
+	return gradientColor(h.GradientColors, position)
+}
+
+// displayColor returns the color a cell is drawn in, taking selection into
+// account
+func (h *Heatmap) displayColor(index int) tcell.Color {
+	if index == h.Selected {
+		fg, _, _ := h.SelectedStyle.Decompose()
+		return fg
+	}
+	return h.cellColor(h.Cells[index].Value)
+}
+
+// gradientColor interpolates smoothly between evenly spaced hex colors
+func gradientColor(colors []string, position float64) tcell.Color {
+	if len(colors) == 0 {
+		return tcell.ColorDefault
+	}
+	if position <= 0 || len(colors) == 1 {
+		return theme.ParseHexColor(colors[0])
+	}
+	if position >= 1 {
+		return theme.ParseHexColor(colors[len(colors)-1])
+	}
+
+	scaled := position * float64(len(colors)-1)
+	segment := int(scaled)
+	return interpolateColor(
+		theme.ParseHexColor(colors[segment]),
+		theme.ParseHexColor(colors[segment+1]),
+		scaled-float64(segment),
+	)
+}
+
+// Draw draws the heatmap
+func (h *Heatmap) Draw() {
+	columns, cellWidth := h.layout()
+	if columns == 0 {
+		return
+	}
+
+	if h.ShowLabels {
+		h.drawLabelled(columns, cellWidth)
+		return
+	}
+
+	perRow := h.cellsPerRow()
+	for i := range h.Cells {
+		gridRow := i / columns
+		if gridRow%perRow != 0 {
+			continue // drawn together with the cell above
+		}
+		col := i % columns
+		y := h.Y + gridRow/perRow
+		if y >= h.Y+h.Height {
+			break
+		}
+
+		style := h.Style.Foreground(h.displayColor(i))
+		r := '▀'
+		if below := i + columns; below < len(h.Cells) {
+			style = style.Background(h.displayColor(below))
+		}
+		for x := 0; x < cellWidth; x++ {
+			h.Screen.SetContent(h.X+col*cellWidth+x, y, r, nil, style)
+		}
+	}
+}
+
+// drawLabelled draws one cell per screen row with its label inside
+func (h *Heatmap) drawLabelled(columns, cellWidth int) {
+	for i, cell := range h.Cells {
+		y := h.Y + i/columns
+		if y >= h.Y+h.Height {
+			break
+		}
+		x := h.X + (i%columns)*cellWidth
+
+		bg := h.displayColor(i)
+		style := h.Style.Background(bg).Foreground(contrastColor(bg))
+		label := []rune(cell.Label)
+		for j := 0; j < cellWidth; j++ {
+			r := ' '
+			if j < len(label) && j < cellWidth-1 {
+				r = label[j]
+			}
+			h.Screen.SetContent(x+j, y, r, nil, style)
+		}
+	}
+}
+
+// contrastColor returns black or white, whichever reads better on bg
+func contrastColor(bg tcell.Color) tcell.Color {
+	r, g, b := bg.RGB()
+	if r < 0 {
+		return tcell.ColorWhite
+	}
+	if 299*r+587*g+114*b > 128000 {
+		return tcell.ColorBlack
+	}
+	return tcell.ColorWhite
+}
+
+// CellAt returns the index of the cell drawn at the given screen position,
+// or -1 if there is none. Without labels a screen row holds two cells and
+// the upper one is returned; see CellsAt for both.
+func (h *Heatmap) CellAt(x, y int) int {
+	upper, _ := h.CellsAt(x, y)
+	return upper
+}
+
+// CellsAt returns the indexes of the upper and lower cell drawn at the
+// given screen position, or -1 for each that isn't there. With labels
+// each screen row holds one cell, so lower is always -1.
+func (h *Heatmap) CellsAt(x, y int) (upper, lower int) {
+	columns, cellWidth := h.layout()
+	if columns == 0 || x < h.X || y < h.Y || y >= h.Y+h.Height {
+		return -1, -1
+	}
+	col := (x - h.X) / cellWidth
+	if col >= columns {
+		return -1, -1
+	}
+	upper = (y-h.Y)*h.cellsPerRow()*columns + col
+	lower = upper + columns
+	if h.cellsPerRow() == 1 || lower >= len(h.Cells) {
+		lower = -1
+	}
+	if upper >= len(h.Cells) {
+		upper = -1
+	}
+	return upper, lower
+}
+
+// pointedCell returns the cell the mouse points at: of the two cells in a
+// half-block row, the lower one if it is selected and the upper one
+// otherwise
+func (h *Heatmap) pointedCell(x, y int) int {
+	upper, lower := h.CellsAt(x, y)
+	if lower >= 0 && lower == h.Selected {
+		return lower
+	}
+	return upper
+}
+
+// GetSelected returns the selected cell
+func (h *Heatmap) GetSelected() (HeatmapCell, bool) {
+	if h.Selected >= 0 && h.Selected < len(h.Cells) {
+		return h.Cells[h.Selected], true
+	}
+	return HeatmapCell{}, false
+}
+
+// GetHovered returns the cell under the mouse pointer
+func (h *Heatmap) GetHovered() (HeatmapCell, bool) {
+	if h.Hovered >= 0 && h.Hovered < len(h.Cells) {
+		return h.Cells[h.Hovered], true
+	}
+	return HeatmapCell{}, false
+}
+
+// Select selects the cell at the given index
+func (h *Heatmap) Select(index int) bool {
+	if index < 0 || index >= len(h.Cells) || index == h.Selected {
+		return false
+	}
+	h.Selected = index
+	if h.OnSelect != nil {
+		h.OnSelect(h.Cells[index])
+	}
+	return true
+}
+
+// SelectKey selects the cell with the given key
+func (h *Heatmap) SelectKey(key string) bool {
+	for i := range h.Cells {
+		if h.Cells[i].Key == key {
+			return h.Select(i)
+		}
+	}
+	return false
+}
+
+// HandleEvent handles keyboard events for moving the selection
+func (h *Heatmap) HandleEvent(ev *tcell.EventKey) bool {
+	columns, _ := h.layout()
+	if columns == 0 {
+		return false
+	}
+	if h.Selected < 0 {
+		// The first navigation key selects the first cell
+		switch ev.Key() {
+		case tcell.KeyLeft, tcell.KeyRight, tcell.KeyUp, tcell.KeyDown, tcell.KeyHome, tcell.KeyEnd:
+			return h.Select(0)
+		}
+		return false
+	}
+
+	switch ev.Key() {
+	case tcell.KeyLeft:
+		if h.Selected%columns > 0 {
+			return h.Select(h.Selected - 1)
+		}
+	case tcell.KeyRight:
+		if h.Selected%columns < columns-1 {
+			return h.Select(h.Selected + 1)
+		}
+	case tcell.KeyUp:
+		return h.Select(h.Selected - columns)
+	case tcell.KeyDown:
+		return h.Select(h.Selected + columns)
+	case tcell.KeyHome:
+		return h.Select(0)
+	case tcell.KeyEnd:
+		return h.Select(len(h.Cells) - 1)
+	}
+	return false
+}
+
+// HandleMouse handles mouse events. Moving the pointer updates the hovered
+// cell and clicking selects it. A terminal can't tell which half of a
+// half-block row the pointer is in, so clicking the selected cell of such
+// a row selects the other cell in it; the arrow keys reach every cell too.
+func (h *Heatmap) HandleMouse(ev *tcell.EventMouse) bool {
+	x, y := ev.Position()
+	index := h.pointedCell(x, y)
+	pressed := ev.Buttons()&tcell.Button1 != 0
+	if pressed && !h.pressed && index >= 0 && index == h.Selected {
+		if upper, lower := h.CellsAt(x, y); lower >= 0 {
+			index = upper + lower - index // the other cell of the pair
+		}
+	}
+	h.pressed = pressed
+
+	handled := false
+	if index != h.Hovered {
+		h.Hovered = index
+		if index >= 0 && h.OnHover != nil {
+			h.OnHover(h.Cells[index])
+		}
+		handled = true
+	}
+	if index >= 0 && pressed {
+		handled = h.Select(index) || handled
+	}
+	return handled
+}
@@ -0,0 +1,213 @@
+package widgets
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// newTestHeatmap creates a heatmap filled with n cells valued 0..n-1
+func newTestHeatmap(screen tcell.Screen, n, width, height int) *Heatmap {
+	h := NewHeatmap(screen, 0, 0, width, height)
+	for i := 0; i < n; i++ {
+		h.AddCell(HeatmapCell{
+			Key:   fmt.Sprintf("cpu%d", i),
+			Label: fmt.Sprintf("%d", i),
+			Value: float64(i),
+		})
+	}
+	return h
+}
+
+func TestNewHeatmap(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewHeatmap(screen, 0, 0, 40, 10)
+	if h == nil {
+		t.Fatal("Expected non-nil heatmap")
+	}
+	if h.Selected != -1 || h.Hovered != -1 {
+		t.Error("Heatmap should start without selection or hover")
+	}
+	if len(h.GradientColors) == 0 {
+		t.Error("Heatmap should have default gradient colors")
+	}
+}
+
+func TestHeatmapLayout(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 128 cells in 80x10 with half blocks: 20 grid rows available
+	h := newTestHeatmap(screen, 128, 80, 10)
+	columns, cellWidth := h.layout()
+	if cellWidth != 8 || columns != 10 {
+		t.Errorf("Expected 10 columns of width 8, got %d of width %d", columns, cellWidth)
+	}
+
+	// Labels take a full row each, so cells must shrink
+	h.SetShowLabels(true)
+	columns, cellWidth = h.layout()
+	if rows := (128 + columns - 1) / columns; rows > 10 {
+		t.Errorf("Labelled layout needs %d rows, only 10 available", rows)
+	}
+	if cellWidth >= 8 {
+		t.Errorf("Expected narrower cells with labels, got width %d", cellWidth)
+	}
+}
+
+func TestHeatmapDraw(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(40, 10)
+
+	h := newTestHeatmap(screen, 4, 4, 1)
+	h.SetRange(0, 3)
+	h.MaxCellWidth = 2
+	h.Draw()
+
+	// Two columns of two rows share a single screen row
+	r, _, style, _ := screen.GetContent(0, 0)
+	if r != '▀' {
+		t.Errorf("Expected upper half block, got %q", r)
+	}
+	fg, bg, _ := style.Decompose()
+	if fg != h.cellColor(0) || bg != h.cellColor(2) {
+		t.Errorf("Expected cells 0 and 2 stacked in the first cell, got %v over %v", fg, bg)
+	}
+}
+
+func TestHeatmapGradient(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewHeatmap(screen, 0, 0, 40, 10)
+	h.SetGradient([]string{"#000000", "#ffffff"})
+	h.SetRange(0, 100)
+
+	r, g, b := h.cellColor(50).RGB()
+	if r != g || g != b || r < 120 || r > 135 {
+		t.Errorf("Expected mid grey at 50%%, got %d,%d,%d", r, g, b)
+	}
+	if h.cellColor(-10) != h.cellColor(0) || h.cellColor(200) != h.cellColor(100) {
+		t.Error("Values outside the range should clamp to the gradient ends")
+	}
+}
+
+func TestHeatmapSelection(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	h := newTestHeatmap(screen, 6, 6, 1)
+	h.MaxCellWidth = 2 // 3 columns, 2 grid rows
+
+	var selected string
+	h.OnSelect = func(cell HeatmapCell) { selected = cell.Key }
+
+	if h.HandleEvent(tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone)) || h.Selected >= 0 {
+		t.Error("Keys other than navigation keys should be left to the caller")
+	}
+	h.HandleEvent(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone))
+	if selected != "cpu0" {
+		t.Errorf("First key press should select the first cell, got %q", selected)
+	}
+	h.HandleEvent(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone))
+	if selected != "cpu3" {
+		t.Errorf("Down should move one grid row, got %q", selected)
+	}
+	if h.HandleEvent(tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone)) {
+		t.Error("Left should not wrap to the previous row")
+	}
+
+	if !h.SelectKey("cpu5") {
+		t.Error("SelectKey should find an existing key")
+	}
+	if cell, ok := h.GetSelected(); !ok || cell.Key != "cpu5" {
+		t.Errorf("Expected cpu5 selected, got %q", cell.Key)
+	}
+}
+
+func TestHeatmapMouse(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	h := newTestHeatmap(screen, 6, 6, 2)
+	h.MaxCellWidth = 2
+	h.SetShowLabels(true)
+
+	var hovered string
+	h.OnHover = func(cell HeatmapCell) { hovered = cell.Key }
+
+	h.HandleMouse(tcell.NewEventMouse(3, 1, tcell.ButtonNone, tcell.ModNone))
+	if hovered != "cpu4" {
+		t.Errorf("Expected hover over cpu4, got %q", hovered)
+	}
+
+	h.HandleMouse(tcell.NewEventMouse(0, 0, tcell.Button1, tcell.ModNone))
+	if cell, ok := h.GetSelected(); !ok || cell.Key != "cpu0" {
+		t.Errorf("Expected click to select cpu0, got %q", cell.Key)
+	}
+
+	if h.CellAt(30, 0) != -1 {
+		t.Error("CellAt outside the grid should return -1")
+	}
+}
+
+func TestHeatmapMouseHalfBlocks(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	h := newTestHeatmap(screen, 4, 4, 1)
+	h.MaxCellWidth = 2 // 2 columns, both grid rows in one screen row
+	if upper, lower := h.CellsAt(2, 0); upper != 1 || lower != 3 {
+		t.Errorf("Expected cells 1 and 3 at (2, 0), got %d and %d", upper, lower)
+	}
+
+	// Clicking the selected cell again selects the one below it
+	click := func(x int) {
+		h.HandleMouse(tcell.NewEventMouse(x, 0, tcell.Button1, tcell.ModNone))
+		h.HandleMouse(tcell.NewEventMouse(x, 0, tcell.ButtonNone, tcell.ModNone))
+	}
+	var seen []int
+	for i := 0; i < 3; i++ {
+		click(2)
+		seen = append(seen, h.Selected)
+	}
+	if seen[0] != 1 || seen[1] != 3 || seen[2] != 1 {
+		t.Errorf("Expected clicks to alternate between cells 1 and 3, got %v", seen)
+	}
+
+	// Holding the button doesn't flip between them
+	h.HandleMouse(tcell.NewEventMouse(0, 0, tcell.Button1, tcell.ModNone))
+	h.HandleMouse(tcell.NewEventMouse(0, 0, tcell.Button1, tcell.ModNone))
+	if h.Selected != 0 {
+		t.Errorf("Expected a held button to keep cell 0, got %d", h.Selected)
+	}
+
+	if h.SelectKey("cpu0") {
+		t.Error("SelectKey should report no change for the selected cell")
+	}
+}
@@ -7,16 +7,26 @@ import (
 	tcell "github.com/gdamore/tcell/v2"
 )
 
-// Meter represents a percentage meter widget
+// ValueFormatter converts a meter value (0-1) into display text
+type ValueFormatter func(value float64) string
+
+// PercentFormatter formats a value (0-1) as a whole percentage
+func PercentFormatter(value float64) string {
+	return fmt.Sprintf("%3.0f%%", value*100)
+}
+
+// Meter represents a percentage meter widget. SetValue fills the bar,
+// growing or shrinking it gradually when SetAnimation is used.
 type Meter struct {
 	X, Y    int
 	Width   int
 	Style   tcell.Style
 	Screen  tcell.Screen
-	Value   float64
 	ShowPct bool
 	Label   string
 
+	animatedValue
+
 	// New fields for block style and gradient
 	BlockStyle   bool
 	BlockSpacing int
@@ -28,19 +38,19 @@ type Meter struct {
 // NewMeter creates a new meter widget
 func NewMeter(screen tcell.Screen, x, y, width int) *Meter {
 	return &Meter{
-		X:            x,
-		Y:            y,
-		Width:        width,
-		Style:        theme.Current.GetStyle(),
-		Screen:       screen,
-		Value:        0,
-		ShowPct:      true,
-		Label:        "",
-		BlockStyle:   false,
-		BlockSpacing: 0,
-		StartColor:   theme.Current.Foreground,
-		EndColor:     theme.Current.Foreground,
-		UseGradient:  false,
+		X:             x,
+		Y:             y,
+		Width:         width,
+		Style:         theme.Current.GetStyle(),
+		Screen:        screen,
+		ShowPct:       true,
+		Label:         "",
+		animatedValue: animatedValue{Formatter: PercentFormatter},
+		BlockStyle:    false,
+		BlockSpacing:  0,
+		StartColor:    theme.Current.Foreground,
+		EndColor:      theme.Current.Foreground,
+		UseGradient:   false,
 	}
 }
 
@@ -63,8 +73,10 @@ func (m *Meter) SetGradient(start, end tcell.Color) {
 
 // Draw draws the meter on the screen
 func (m *Meter) Draw() {
+	value := m.DisplayValue()
+
 	// Calculate filled width
-	filledWidth := int(float64(m.Width) * m.Value)
+	filledWidth := int(float64(m.Width) * value)
 	if filledWidth > m.Width {
 		filledWidth = m.Width
 	}
@@ -77,7 +89,7 @@ func (m *Meter) Draw() {
 			blockWidth += m.BlockSpacing
 		}
 		numBlocks := m.Width / blockWidth
-		filledBlocks := int(float64(numBlocks) * m.Value)
+		filledBlocks := int(float64(numBlocks) * value)
 
 		for i := 0; i < numBlocks; i++ {
 			x := m.X + i*blockWidth
@@ -118,23 +130,12 @@ func (m *Meter) Draw() {
 
 	// Draw percentage if enabled
 	if m.ShowPct {
-		text := fmt.Sprintf("%3.0f%%", m.Value*100)
+		text := m.FormatValue()
 		textStyle := theme.Current.GetStyle()
 		m.drawTextStyled(m.X+m.Width+1, m.Y, text, textStyle)
 	}
 }
 
-// SetValue sets the current value of the meter (0-1)
-func (m *Meter) SetValue(value float64) {
-	if value < 0 {
-		value = 0
-	}
-	if value > 1 {
-		value = 1
-	}
-	m.Value = value
-}
-
 // SetStyle sets the style for the meter
 func (m *Meter) SetStyle(style tcell.Style) {
 	m.Style = style
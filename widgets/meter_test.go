@@ -86,3 +86,22 @@ func TestMeterSetLabel(t *testing.T) {
 		t.Errorf("Expected label to be %q, got %q", testLabel, meter.Label)
 	}
 }
+
+func TestMeterFormatter(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	meter := NewMeter(screen, 0, 0, 50)
+	meter.SetValue(0.5)
+	if got := meter.FormatValue(); got != " 50%" {
+		t.Errorf("Expected default format \" 50%%\", got %q", got)
+	}
+
+	meter.SetFormatter(func(value float64) string { return "half" })
+	if got := meter.FormatValue(); got != "half" {
+		t.Errorf("Expected custom format \"half\", got %q", got)
+	}
+}
@@ -15,6 +15,8 @@ type MeterItem struct {
 	Style          tcell.Style
 	GradientColors []tcell.Color
 	Height         int
+
+	tween tween
 }
 
 // MultiMeter represents a multi meter widget
@@ -33,6 +35,9 @@ type MultiMeter struct {
 	LabelWidth  int
 	MeterHeight int
 	Spacing     int
+
+	// Animation, when set, makes UpdateMeter tween towards the new value
+	Animation *Animation
 }
 
 // Orientation represents the orientation of the multi meter
@@ -129,7 +134,7 @@ func (m *MultiMeter) drawLabel(x, y int, item MeterItem) {
 // drawMeterBar draws a meter bar for a meter item
 func (m *MultiMeter) drawMeterBar(x, y, width int, item MeterItem) {
 	// Calculate value percentage
-	percentage := item.Value / item.MaxValue
+	percentage := m.displayValue(item) / item.MaxValue
 	if percentage > 1.0 {
 		percentage = 1.0
 	}
@@ -158,7 +163,7 @@ func (m *MultiMeter) drawMeterBar(x, y, width int, item MeterItem) {
 
 // drawValue draws a value for a meter item
 func (m *MultiMeter) drawValue(x, y int, item MeterItem) {
-	text := fmt.Sprintf("%.1f%%", item.Value)
+	text := fmt.Sprintf("%.1f%%", m.displayValue(item))
 	style := m.Style
 	if item.Style != (tcell.Style{}) {
 		style = item.Style
@@ -329,8 +334,22 @@ func (m *MultiMeter) UpdateMeter(label string, value float64) {
 
 	for i := range m.Items {
 		if m.Items[i].Label == label {
-			m.Items[i].Value = value
+			item := &m.Items[i]
+			item.tween = m.Animation.start(item.tween.settle(item.Value), value)
+			item.Value = value
 			return
 		}
 	}
 }
+
+// SetAnimation enables animated transitions for UpdateMeter.
+// Passing nil makes the meters snap to new values.
+func (m *MultiMeter) SetAnimation(animation *Animation) {
+	m.Animation = animation
+}
+
+// displayValue returns the value currently shown for an item, which trails
+// its Value while an animation is running
+func (m *MultiMeter) displayValue(item MeterItem) float64 {
+	return item.tween.settle(item.Value).value(m.Animation.now())
+}
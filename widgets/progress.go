@@ -0,0 +1,326 @@
+package widgets
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/deadjoe/termdodo/symbols"
+	"github.com/deadjoe/termdodo/theme"
+	"github.com/gdamore/tcell/v2"
+)
+
+// DefaultRateWindow is the time span used to measure throughput
+const DefaultRateWindow = 5 * time.Second
+
+// progressSample records the done count at a point in time
+type progressSample struct {
+	at   time.Time
+	done int64
+}
+
+// Progress represents a progress bar that tracks done/total counts
+type Progress struct {
+	X, Y     int
+	Width    int
+	Screen   tcell.Screen
+	Style    tcell.Style
+	BarStyle tcell.Style
+
+	Label         string
+	Done          int64
+	Total         int64
+	Indeterminate bool
+	ShowRate      bool
+	ShowETA       bool
+	RateUnit      string
+	RateWindow    time.Duration
+	StatusWidth   int
+
+	started time.Time
+	samples []progressSample
+	now     func() time.Time
+}
+
+// NewProgress creates a new progress bar widget
+func NewProgress(screen tcell.Screen, x, y, width int) *Progress {
+	p := &Progress{
+		X:           x,
+		Y:           y,
+		Width:       width,
+		Screen:      screen,
+		Style:       theme.Current.GetStyle(),
+		BarStyle:    theme.Current.GetAccentStyle(),
+		ShowRate:    true,
+		ShowETA:     true,
+		RateUnit:    "/s",
+		RateWindow:  DefaultRateWindow,
+		StatusWidth: 10,
+		now:         time.Now,
+	}
+	p.Reset()
+	return p
+}
+
+// Reset clears the done count and restarts timing
+func (p *Progress) Reset() {
+	p.Done = 0
+	p.started = p.now()
+	p.samples = []progressSample{{at: p.started}}
+}
+
+// SetLabel sets the label drawn before the bar
+func (p *Progress) SetLabel(label string) {
+	p.Label = label
+}
+
+// SetTotal sets the total count. A total of zero or less makes the
+// progress indeterminate until a total is known.
+func (p *Progress) SetTotal(total int64) {
+	p.Total = total
+}
+
+// SetDone sets the done count
+func (p *Progress) SetDone(done int64) {
+	if done < 0 {
+		done = 0
+	}
+	p.Done = done
+	p.record()
+}
+
+// Add increments the done count
+func (p *Progress) Add(n int64) {
+	p.SetDone(p.Done + n)
+}
+
+// SetIndeterminate sets whether to show a bouncing bar instead of a fill
+func (p *Progress) SetIndeterminate(indeterminate bool) {
+	p.Indeterminate = indeterminate
+}
+
+// SetShowRate sets whether to show the throughput
+func (p *Progress) SetShowRate(show bool) {
+	p.ShowRate = show
+}
+
+// SetShowETA sets whether to show the estimated time remaining
+func (p *Progress) SetShowETA(show bool) {
+	p.ShowETA = show
+}
+
+// SetStyle sets the style for the text and the empty part of the bar
+func (p *Progress) SetStyle(style tcell.Style) {
+	p.Style = style
+}
+
+// SetBarStyle sets the style for the filled part of the bar
+func (p *Progress) SetBarStyle(style tcell.Style) {
+	p.BarStyle = style
+}
+
+// record adds a throughput sample and drops samples outside the window
+func (p *Progress) record() {
+	now := p.now()
+	p.samples = append(p.samples, progressSample{at: now, done: p.Done})
+
+	cutoff := now.Add(-p.RateWindow)
+	drop := 0
+	for drop < len(p.samples)-2 && p.samples[drop+1].at.Before(cutoff) {
+		drop++
+	}
+	p.samples = p.samples[drop:]
+}
+
+// indeterminate returns whether the bar shows activity rather than a fill
+func (p *Progress) indeterminate() bool {
+	return p.Indeterminate || p.Total <= 0
+}
+
+// Fraction returns the completed fraction (0-1)
+func (p *Progress) Fraction() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	fraction := float64(p.Done) / float64(p.Total)
+	if fraction > 1 {
+		fraction = 1
+	}
+	return fraction
+}
+
+// Rate returns the throughput in items per second over the rate window
+func (p *Progress) Rate() float64 {
+	if len(p.samples) < 2 {
+		return 0
+	}
+	first := p.samples[0]
+	last := p.samples[len(p.samples)-1]
+	elapsed := last.at.Sub(first.at).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(last.done-first.done) / elapsed
+}
+
+// ETA returns the estimated time remaining. It reports false while the
+// estimate is unknown.
+func (p *Progress) ETA() (time.Duration, bool) {
+	if p.indeterminate() {
+		return 0, false
+	}
+	remaining := p.Total - p.Done
+	if remaining <= 0 {
+		return 0, true
+	}
+	rate := p.Rate()
+	if rate <= 0 {
+		return 0, false
+	}
+	return time.Duration(float64(remaining) / rate * float64(time.Second)), true
+}
+
+// Elapsed returns the time since the progress was started or reset
+func (p *Progress) Elapsed() time.Duration {
+	return p.now().Sub(p.started)
+}
+
+// info returns the text shown after the bar
+func (p *Progress) info() string {
+	parts := make([]string, 0, 3)
+	if p.indeterminate() {
+		parts = append(parts, fmt.Sprintf("%d", p.Done))
+	} else {
+		parts = append(parts, fmt.Sprintf("%3.0f%%", p.Fraction()*100))
+	}
+	if p.ShowRate {
+		parts = append(parts, formatCount(p.Rate())+p.RateUnit)
+	}
+	if p.ShowETA && !p.indeterminate() {
+		if eta, ok := p.ETA(); ok {
+			parts = append(parts, "ETA "+formatDuration(eta))
+		} else {
+			parts = append(parts, "ETA --:--")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// bar renders the bar into width cells and returns its runes and the
+// range of cells drawn in the bar style
+func (p *Progress) bar(width int) ([]rune, int, int) {
+	cells := make([]rune, width)
+	for i := range cells {
+		cells[i] = ' '
+	}
+	if width <= 0 {
+		return cells, 0, 0
+	}
+
+	if p.indeterminate() {
+		// A block a fifth of the bar wide bounces between both ends
+		size := width / 5
+		if size < 1 {
+			size = 1
+		}
+		travel := width - size
+		pos := 0
+		if travel > 0 {
+			step := int(p.Elapsed() / (50 * time.Millisecond))
+			pos = step % (2 * travel)
+			if pos > travel {
+				pos = 2*travel - pos
+			}
+		}
+		for i := pos; i < pos+size; i++ {
+			cells[i] = symbols.BlockFull
+		}
+		return cells, pos, pos + size
+	}
+
+	eighths := int(p.Fraction() * float64(width*subCells))
+	full := eighths / subCells
+	for i := 0; i < full; i++ {
+		cells[i] = symbols.BlockFull
+	}
+	end := full
+	if rest := eighths % subCells; rest > 0 && full < width {
+		cells[full] = []rune(symbols.BlockLeftPatterns[rest])[0]
+		end++
+	}
+	return cells, 0, end
+}
+
+// Draw draws the progress bar
+func (p *Progress) Draw() {
+	x := p.X
+	maxX := p.X + p.Width
+
+	if p.Label != "" {
+		for _, r := range p.Label + " " {
+			if x >= maxX {
+				return
+			}
+			p.Screen.SetContent(x, p.Y, r, nil, p.Style)
+			x++
+		}
+	}
+
+	info := []rune(" " + p.info())
+	barWidth := maxX - x - len(info)
+	if barWidth < 1 {
+		barWidth = maxX - x
+		info = nil
+	}
+
+	cells, start, end := p.bar(barWidth)
+	for i, r := range cells {
+		style := p.Style
+		if i >= start && i < end {
+			style = p.BarStyle
+		}
+		p.Screen.SetContent(x+i, p.Y, r, nil, style)
+	}
+	x += len(cells)
+
+	for _, r := range info {
+		p.Screen.SetContent(x, p.Y, r, nil, p.Style)
+		x++
+	}
+}
+
+// StatusText returns a compact rendering for use in a status bar item
+func (p *Progress) StatusText() string {
+	cells, _, _ := p.bar(p.StatusWidth)
+	text := "[" + string(cells) + "] " + p.info()
+	if p.Label != "" {
+		text = p.Label + " " + text
+	}
+	return text
+}
+
+// formatCount formats a count with a k/M/G suffix
+func formatCount(value float64) string {
+	switch {
+	case value >= 1e9:
+		return fmt.Sprintf("%.1fG", value/1e9)
+	case value >= 1e6:
+		return fmt.Sprintf("%.1fM", value/1e6)
+	case value >= 1e3:
+		return fmt.Sprintf("%.1fk", value/1e3)
+	default:
+		return fmt.Sprintf("%.1f", value)
+	}
+}
+
+// formatDuration formats a duration as m:ss or h:mm:ss
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := int(d / time.Hour)
+	m := int(d % time.Hour / time.Minute)
+	s := int(d % time.Minute / time.Second)
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
@@ -0,0 +1,173 @@
+package widgets
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deadjoe/termdodo/symbols"
+	"github.com/gdamore/tcell/v2"
+)
+
+// newTestProgress creates a progress bar whose time is controlled by the test
+func newTestProgress(screen tcell.Screen, now *time.Time, width int) *Progress {
+	p := NewProgress(screen, 0, 0, width)
+	p.now = func() time.Time { return *now }
+	p.Reset()
+	return p
+}
+
+func TestNewProgress(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewProgress(screen, 0, 0, 40)
+	if p == nil {
+		t.Fatal("Expected non-nil progress")
+	}
+	if p.Done != 0 || p.Fraction() != 0 {
+		t.Error("Progress should start empty")
+	}
+	if !p.indeterminate() {
+		t.Error("Progress without a total should be indeterminate")
+	}
+}
+
+func TestProgressRateAndETA(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(0, 0)
+	p := newTestProgress(screen, &now, 40)
+	p.SetTotal(100)
+
+	if _, ok := p.ETA(); ok {
+		t.Error("ETA should be unknown before any progress")
+	}
+
+	now = now.Add(2 * time.Second)
+	p.Add(20)
+
+	if got := p.Fraction(); got != 0.2 {
+		t.Errorf("Expected fraction 0.2, got %f", got)
+	}
+	if got := p.Rate(); got != 10 {
+		t.Errorf("Expected rate 10/s, got %f", got)
+	}
+	eta, ok := p.ETA()
+	if !ok || eta != 8*time.Second {
+		t.Errorf("Expected ETA 8s, got %v (%v)", eta, ok)
+	}
+
+	// The start sample falls out of the rate window
+	now = now.Add(10 * time.Second)
+	p.Add(50)
+	if got := p.Rate(); got != 5 {
+		t.Errorf("Expected rate over the window to be 5/s, got %f", got)
+	}
+
+	p.SetDone(150)
+	if p.Fraction() != 1 {
+		t.Error("Fraction should clamp to 1")
+	}
+}
+
+func TestProgressDraw(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(40, 5)
+
+	now := time.Unix(0, 0)
+	p := newTestProgress(screen, &now, 40)
+	p.SetShowRate(false)
+	p.SetShowETA(false)
+	p.SetLabel("copy")
+	p.SetTotal(4)
+	p.SetDone(1)
+	p.Draw()
+
+	row := readRow(screen, 0, 0, 40)
+	if !strings.HasPrefix(row, "copy ") || !strings.HasSuffix(row, " 25%") {
+		t.Errorf("Unexpected progress row %q", row)
+	}
+	// 30 bar cells at 25% fill seven and a half cells
+	if r, _, _, _ := screen.GetContent(5+6, 0); r != symbols.BlockFull {
+		t.Errorf("Expected full block inside the fill, got %q", r)
+	}
+	if r, _, _, _ := screen.GetContent(5+7, 0); r != symbols.BlockLeft4 {
+		t.Errorf("Expected half block at the end of the fill, got %q", r)
+	}
+}
+
+func TestProgressIndeterminate(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(0, 0)
+	p := newTestProgress(screen, &now, 40)
+	p.SetIndeterminate(true)
+
+	_, start, _ := p.bar(20)
+	if start != 0 {
+		t.Errorf("Bouncing block should start at the left edge, got %d", start)
+	}
+	now = now.Add(16 * 50 * time.Millisecond)
+	_, start, end := p.bar(20)
+	if start != 16 || end != 20 {
+		t.Errorf("Bouncing block should reach the right edge, got %d-%d", start, end)
+	}
+	now = now.Add(4 * 50 * time.Millisecond)
+	if _, start, _ = p.bar(20); start != 12 {
+		t.Errorf("Bouncing block should move back left, got %d", start)
+	}
+}
+
+func TestProgressStatusText(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(0, 0)
+	p := newTestProgress(screen, &now, 40)
+	p.SetTotal(10)
+	p.SetDone(5)
+	p.SetShowRate(false)
+	p.SetShowETA(false)
+
+	if got := p.StatusText(); got != "[█████     ]  50%" {
+		t.Errorf("Unexpected status text %q", got)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{0, "0:00"},
+		{65 * time.Second, "1:05"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "1:02:03"},
+	}
+
+	for _, tc := range testCases {
+		if got := formatDuration(tc.d); got != tc.expected {
+			t.Errorf("formatDuration(%v) = %q, want %q", tc.d, got, tc.expected)
+		}
+	}
+}
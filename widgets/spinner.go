@@ -0,0 +1,110 @@
+package widgets
+
+import (
+	"time"
+
+	"github.com/deadjoe/termdodo/symbols"
+	"github.com/deadjoe/termdodo/theme"
+	"github.com/gdamore/tcell/v2"
+)
+
+// DefaultSpinnerInterval is the time each spinner frame is shown
+const DefaultSpinnerInterval = 80 * time.Millisecond
+
+// Spinner represents an activity indicator that cycles through frames
+type Spinner struct {
+	X, Y     int
+	Screen   tcell.Screen
+	Style    tcell.Style
+	Frames   []string
+	Interval time.Duration
+	Label    string
+	Active   bool
+
+	started time.Time
+	now     func() time.Time
+}
+
+// NewSpinner creates a new spinner using braille frames
+func NewSpinner(screen tcell.Screen, x, y int) *Spinner {
+	return &Spinner{
+		X:        x,
+		Y:        y,
+		Screen:   screen,
+		Style:    theme.Current.GetAccentStyle(),
+		Frames:   symbols.SpinnerBraille,
+		Interval: DefaultSpinnerInterval,
+		now:      time.Now,
+	}
+}
+
+// SetFrames sets the frames the spinner cycles through
+func (s *Spinner) SetFrames(frames []string) {
+	if len(frames) == 0 {
+		frames = symbols.SpinnerBraille
+	}
+	s.Frames = frames
+}
+
+// SetInterval sets the time each frame is shown
+func (s *Spinner) SetInterval(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultSpinnerInterval
+	}
+	s.Interval = interval
+}
+
+// SetLabel sets the text drawn after the spinner
+func (s *Spinner) SetLabel(label string) {
+	s.Label = label
+}
+
+// SetStyle sets the style for the spinner
+func (s *Spinner) SetStyle(style tcell.Style) {
+	s.Style = style
+}
+
+// Start starts the animation from the first frame
+func (s *Spinner) Start() {
+	s.Active = true
+	s.started = s.now()
+}
+
+// Stop stops the animation. A stopped spinner draws only its label.
+func (s *Spinner) Stop() {
+	s.Active = false
+}
+
+// Frame returns the frame to show at the current time
+func (s *Spinner) Frame() string {
+	if !s.Active || len(s.Frames) == 0 {
+		return " "
+	}
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultSpinnerInterval
+	}
+	step := int(s.now().Sub(s.started) / interval)
+	return s.Frames[step%len(s.Frames)]
+}
+
+// Draw draws the spinner
+func (s *Spinner) Draw() {
+	x := s.X
+	for _, r := range s.StatusText() {
+		s.Screen.SetContent(x, s.Y, r, nil, s.Style)
+		x++
+	}
+}
+
+// StatusText returns the current frame and label for use in a status bar
+// item, or just the label once stopped
+func (s *Spinner) StatusText() string {
+	if !s.Active {
+		return s.Label
+	}
+	if s.Label == "" {
+		return s.Frame()
+	}
+	return s.Frame() + " " + s.Label
+}
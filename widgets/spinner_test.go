@@ -0,0 +1,86 @@
+package widgets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deadjoe/termdodo/symbols"
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestNewSpinner(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewSpinner(screen, 0, 0)
+	if s == nil {
+		t.Fatal("Expected non-nil spinner")
+	}
+	if len(s.Frames) != len(symbols.SpinnerBraille) {
+		t.Error("Spinner should default to braille frames")
+	}
+	if s.Frame() != " " {
+		t.Error("Stopped spinner should show a blank frame")
+	}
+}
+
+func TestSpinnerFrames(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(0, 0)
+	s := NewSpinner(screen, 0, 0)
+	s.now = func() time.Time { return now }
+	s.SetFrames(symbols.SpinnerLine)
+	s.SetInterval(100 * time.Millisecond)
+	s.Start()
+
+	expected := []string{"|", "/", "-", "\\", "|"}
+	for i, frame := range expected {
+		if got := s.Frame(); got != frame {
+			t.Errorf("Step %d: expected frame %q, got %q", i, frame, got)
+		}
+		now = now.Add(100 * time.Millisecond)
+	}
+
+	s.SetLabel("loading")
+	if got := s.StatusText(); got != "/ loading" {
+		t.Errorf("Unexpected status text %q", got)
+	}
+
+	s.Stop()
+	if s.Active {
+		t.Error("Stop should deactivate the spinner")
+	}
+	if got := s.StatusText(); got != "loading" {
+		t.Errorf("Stopped spinner should show only its label, got %q", got)
+	}
+}
+
+func TestSpinnerInStatusBar(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(40, 1)
+
+	s := NewSpinner(screen, 0, 0)
+	s.SetLabel("sync")
+	s.Start()
+
+	bar := NewStatusBar(screen, 0, 0, 40)
+	bar.SetPadding(0)
+	bar.AddItem(StatusItem{Provider: s})
+	bar.Draw()
+
+	if got := readRow(screen, 0, 0, 6); got != s.StatusText() {
+		t.Errorf("Expected status bar to show %q, got %q", s.StatusText(), got)
+	}
+}
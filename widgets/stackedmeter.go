@@ -0,0 +1,305 @@
+package widgets
+
+import (
+	"fmt"
+
+	"github.com/deadjoe/termdodo/symbols"
+	"github.com/deadjoe/termdodo/theme"
+	"github.com/gdamore/tcell/v2"
+)
+
+// MeterSegment represents a single colored segment of a stacked meter
+type MeterSegment struct {
+	Label string
+	Value float64
+	Color tcell.Color
+}
+
+// StackedMeter represents a meter made of several colored segments
+type StackedMeter struct {
+	X, Y          int
+	Width, Height int
+	Screen        tcell.Screen
+	Style         tcell.Style
+	LabelStyle    tcell.Style
+
+	Segments    []MeterSegment
+	MaxValue    float64
+	Orientation Orientation
+	Thickness   int
+	ShowLegend  bool
+}
+
+// segmentPalette is used for segments without an explicit color
+var segmentPalette = []tcell.Color{
+	tcell.ColorGreen,
+	tcell.ColorRed,
+	tcell.ColorYellow,
+	tcell.ColorBlue,
+	tcell.ColorPurple,
+	tcell.ColorTeal,
+}
+
+// subCells is the number of sub-cell steps available in a block character
+const subCells = 8
+
+// NewStackedMeter creates a new stacked meter widget
+func NewStackedMeter(screen tcell.Screen, x, y, width, height int) *StackedMeter {
+	return &StackedMeter{
+		X:           x,
+		Y:           y,
+		Width:       width,
+		Height:      height,
+		Screen:      screen,
+		Style:       theme.Current.GetStyle(),
+		LabelStyle:  theme.Current.GetStyle(),
+		Segments:    make([]MeterSegment, 0),
+		MaxValue:    100,
+		Orientation: Horizontal,
+		Thickness:   1,
+		ShowLegend:  true,
+	}
+}
+
+// SetSegments sets the meter segments
+func (m *StackedMeter) SetSegments(segments []MeterSegment) {
+	m.Segments = segments
+}
+
+// AddSegment adds a segment to the end of the meter
+func (m *StackedMeter) AddSegment(segment MeterSegment) {
+	m.Segments = append(m.Segments, segment)
+}
+
+// ClearSegments removes all segments
+func (m *StackedMeter) ClearSegments() {
+	m.Segments = nil
+}
+
+// UpdateSegment updates the value of the segment with the given label
+func (m *StackedMeter) UpdateSegment(label string, value float64) {
+	if value < 0 {
+		value = 0
+	}
+	for i := range m.Segments {
+		if m.Segments[i].Label == label {
+			m.Segments[i].Value = value
+			return
+		}
+	}
+}
+
+// SetMaxValue sets the value that corresponds to a full bar.
+// A value of zero or less scales the bar to the sum of all segments.
+func (m *StackedMeter) SetMaxValue(max float64) {
+	m.MaxValue = max
+}
+
+// SetOrientation sets the direction the bar grows in
+func (m *StackedMeter) SetOrientation(orientation Orientation) {
+	m.Orientation = orientation
+}
+
+// SetThickness sets the number of cells across the bar
+func (m *StackedMeter) SetThickness(thickness int) {
+	if thickness <= 0 {
+		thickness = 1
+	}
+	m.Thickness = thickness
+}
+
+// SetShowLegend sets whether to show the legend
+func (m *StackedMeter) SetShowLegend(show bool) {
+	m.ShowLegend = show
+}
+
+// SetStyle sets the style for the empty part of the meter
+func (m *StackedMeter) SetStyle(style tcell.Style) {
+	m.Style = style
+}
+
+// SetLabelStyle sets the style for legend labels
+func (m *StackedMeter) SetLabelStyle(style tcell.Style) {
+	m.LabelStyle = style
+}
+
+// Total returns the sum of all segment values
+func (m *StackedMeter) Total() float64 {
+	total := 0.0
+	for _, seg := range m.Segments {
+		if seg.Value > 0 {
+			total += seg.Value
+		}
+	}
+	return total
+}
+
+// scale returns the value that corresponds to a full bar
+func (m *StackedMeter) scale() float64 {
+	if m.MaxValue > 0 {
+		return m.MaxValue
+	}
+	return m.Total()
+}
+
+// segmentColor returns the display color of the segment at index i
+func (m *StackedMeter) segmentColor(i int) tcell.Color {
+	if m.Segments[i].Color != tcell.ColorDefault {
+		return m.Segments[i].Color
+	}
+	return segmentPalette[i%len(segmentPalette)]
+}
+
+// boundaries returns the end of each segment measured in sub-cells
+func (m *StackedMeter) boundaries(length int) []int {
+	bounds := make([]int, len(m.Segments))
+	scale := m.scale()
+	if scale <= 0 {
+		return bounds
+	}
+
+	total := length * subCells
+	cumulative := 0.0
+	for i, seg := range m.Segments {
+		if seg.Value > 0 {
+			cumulative += seg.Value
+		}
+		end := int(cumulative/scale*float64(total) + 0.5)
+		if end > total {
+			end = total
+		}
+		bounds[i] = end
+	}
+	return bounds
+}
+
+// segmentAt returns the index of the segment covering the given sub-cell,
+// or -1 if the sub-cell is past the end of the last segment
+func segmentAt(bounds []int, pos int) int {
+	for i, end := range bounds {
+		if pos < end {
+			return i
+		}
+	}
+	return -1
+}
+
+// cellContent returns the rune and style of the cell starting at the given
+// sub-cell. The leading segment is drawn in the foreground and the segment
+// that follows it inside the same cell, if any, in the background.
+func (m *StackedMeter) cellContent(bounds []int, start int, patterns []string) (rune, tcell.Style) {
+	lead := segmentAt(bounds, start)
+	if lead < 0 {
+		return ' ', m.Style
+	}
+
+	// Find where the leading segment stops inside this cell
+	fill := subCells
+	if end := bounds[lead]; end < start+subCells {
+		fill = end - start
+	}
+
+	style := m.Style.Foreground(m.segmentColor(lead))
+	if fill < subCells {
+		if next := segmentAt(bounds, start+fill); next >= 0 {
+			style = style.Background(m.segmentColor(next))
+		}
+	}
+	return []rune(patterns[fill])[0], style
+}
+
+// Draw draws the stacked meter
+func (m *StackedMeter) Draw() {
+	if m.Width <= 0 || m.Height <= 0 {
+		return
+	}
+
+	switch m.Orientation {
+	case Vertical:
+		m.drawVertical()
+	case Horizontal:
+		m.drawHorizontal()
+	}
+}
+
+// drawHorizontal draws the bar left to right with the legend below it
+func (m *StackedMeter) drawHorizontal() {
+	bounds := m.boundaries(m.Width)
+	thickness := m.Thickness
+	if thickness > m.Height {
+		thickness = m.Height
+	}
+
+	for i := 0; i < m.Width; i++ {
+		r, style := m.cellContent(bounds, i*subCells, symbols.BlockLeftPatterns)
+		for j := 0; j < thickness; j++ {
+			m.Screen.SetContent(m.X+i, m.Y+j, r, nil, style)
+		}
+	}
+
+	if m.ShowLegend && thickness < m.Height {
+		x := m.X
+		for i := range m.Segments {
+			entry := []rune(m.legendEntry(i))
+			if x+len(entry) > m.X+m.Width {
+				break
+			}
+			x = m.drawLegendEntry(x, m.Y+thickness, i)
+			x++
+		}
+	}
+}
+
+// drawVertical draws the bar bottom to top with the legend beside it
+func (m *StackedMeter) drawVertical() {
+	bounds := m.boundaries(m.Height)
+	thickness := m.Thickness
+	if thickness > m.Width {
+		thickness = m.Width
+	}
+
+	for i := 0; i < m.Height; i++ {
+		r, style := m.cellContent(bounds, i*subCells, symbols.BlockPatterns)
+		y := m.Y + m.Height - 1 - i
+		for j := 0; j < thickness; j++ {
+			m.Screen.SetContent(m.X+j, y, r, nil, style)
+		}
+	}
+
+	if m.ShowLegend && thickness+1 < m.Width {
+		for i := range m.Segments {
+			if i >= m.Height {
+				break
+			}
+			m.drawLegendEntry(m.X+thickness+1, m.Y+i, i)
+		}
+	}
+}
+
+// legendEntry returns the legend text for the segment at index i
+func (m *StackedMeter) legendEntry(i int) string {
+	seg := m.Segments[i]
+	pct := 0.0
+	if scale := m.scale(); scale > 0 {
+		pct = seg.Value / scale * 100
+	}
+	return fmt.Sprintf("■ %s %.1f%%", seg.Label, pct)
+}
+
+// drawLegendEntry draws the legend entry for segment i and returns the
+// x position after it
+func (m *StackedMeter) drawLegendEntry(x, y, i int) int {
+	maxX := m.X + m.Width
+	for j, r := range []rune(m.legendEntry(i)) {
+		if x >= maxX {
+			break
+		}
+		style := m.LabelStyle
+		if j == 0 {
+			style = style.Foreground(m.segmentColor(i))
+		}
+		m.Screen.SetContent(x, y, r, nil, style)
+		x++
+	}
+	return x
+}
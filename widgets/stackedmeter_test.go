@@ -0,0 +1,155 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/deadjoe/termdodo/symbols"
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestNewStackedMeter(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewStackedMeter(screen, 0, 0, 20, 2)
+	if m == nil {
+		t.Fatal("Expected non-nil stacked meter")
+	}
+	if m.MaxValue != 100 {
+		t.Errorf("Expected default MaxValue 100, got %f", m.MaxValue)
+	}
+	if m.Orientation != Horizontal {
+		t.Error("Default orientation should be Horizontal")
+	}
+}
+
+func TestStackedMeterSegments(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewStackedMeter(screen, 0, 0, 20, 2)
+	m.SetSegments([]MeterSegment{
+		{Label: "user", Value: 30},
+		{Label: "system", Value: 10},
+	})
+	m.AddSegment(MeterSegment{Label: "iowait", Value: 5})
+
+	if len(m.Segments) != 3 {
+		t.Fatalf("Expected 3 segments, got %d", len(m.Segments))
+	}
+	if m.Total() != 45 {
+		t.Errorf("Expected total 45, got %f", m.Total())
+	}
+
+	m.UpdateSegment("system", 20)
+	if m.Segments[1].Value != 20 {
+		t.Errorf("UpdateSegment failed, got %f", m.Segments[1].Value)
+	}
+	m.UpdateSegment("user", -5)
+	if m.Segments[0].Value != 0 {
+		t.Errorf("UpdateSegment should clamp negative values, got %f", m.Segments[0].Value)
+	}
+
+	m.ClearSegments()
+	if len(m.Segments) != 0 {
+		t.Error("ClearSegments failed")
+	}
+}
+
+func TestStackedMeterBoundaries(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewStackedMeter(screen, 0, 0, 10, 1)
+	m.SetSegments([]MeterSegment{
+		{Label: "a", Value: 25},
+		{Label: "b", Value: 50},
+	})
+
+	bounds := m.boundaries(10)
+	if bounds[0] != 20 || bounds[1] != 60 {
+		t.Errorf("Expected boundaries [20 60], got %v", bounds)
+	}
+
+	// With no maximum the segments fill the whole bar
+	m.SetMaxValue(0)
+	bounds = m.boundaries(10)
+	if bounds[1] != 80 {
+		t.Errorf("Expected last boundary 80, got %d", bounds[1])
+	}
+}
+
+func TestStackedMeterDrawHorizontal(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(40, 10)
+
+	m := NewStackedMeter(screen, 0, 0, 10, 2)
+	m.SetSegments([]MeterSegment{
+		{Label: "a", Value: 25, Color: tcell.ColorRed},
+		{Label: "b", Value: 50, Color: tcell.ColorBlue},
+	})
+	m.Draw()
+
+	// 25% of 10 cells ends halfway through the third cell
+	r, _, style, _ := screen.GetContent(2, 0)
+	if r != symbols.BlockLeft4 {
+		t.Errorf("Expected half block at boundary, got %q", r)
+	}
+	fg, bg, _ := style.Decompose()
+	if fg != tcell.ColorRed || bg != tcell.ColorBlue {
+		t.Errorf("Expected red on blue at boundary, got %v on %v", fg, bg)
+	}
+
+	r, _, _, _ = screen.GetContent(9, 0)
+	if r != ' ' {
+		t.Errorf("Expected empty cell past the last segment, got %q", r)
+	}
+
+	r, _, style, _ = screen.GetContent(0, 1)
+	fg, _, _ = style.Decompose()
+	if r != '■' || fg != tcell.ColorRed {
+		t.Errorf("Expected red legend marker, got %q in %v", r, fg)
+	}
+}
+
+func TestStackedMeterDrawVertical(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(40, 10)
+
+	m := NewStackedMeter(screen, 0, 0, 20, 4)
+	m.SetOrientation(Vertical)
+	m.SetSegments([]MeterSegment{
+		{Label: "used", Value: 50, Color: tcell.ColorGreen},
+	})
+	m.Draw()
+
+	r, _, _, _ := screen.GetContent(0, 3)
+	if r != symbols.BlockFull {
+		t.Errorf("Expected full block at the bottom, got %q", r)
+	}
+	r, _, _, _ = screen.GetContent(0, 0)
+	if r != ' ' {
+		t.Errorf("Expected empty cell at the top, got %q", r)
+	}
+	r, _, _, _ = screen.GetContent(2, 0)
+	if r != '■' {
+		t.Errorf("Expected legend beside the bar, got %q", r)
+	}
+}
@@ -6,6 +6,12 @@ import (
 	"strings"
 )
 
+// StatusProvider supplies live text for a status bar item, such as a
+// Progress or Spinner
+type StatusProvider interface {
+	StatusText() string
+}
+
 // StatusItem represents a single item in the status bar
 type StatusItem struct {
 	Text      string
@@ -13,6 +19,7 @@ type StatusItem struct {
 	MinWidth  int
 	MaxWidth  int
 	Alignment Alignment
+	Provider  StatusProvider // Overrides Text when set
 }
 
 // StatusBar represents a status bar widget
@@ -74,7 +81,7 @@ func (s *StatusBar) calculateItemWidths() (int, int) {
 	for _, item := range s.Items {
 		minWidth := item.MinWidth
 		if minWidth == 0 {
-			minWidth = len(item.Text) + s.Padding*2
+			minWidth = len([]rune(itemText(item))) + s.Padding*2
 		}
 		totalMinWidth += minWidth
 		if item.MaxWidth > minWidth {
@@ -100,12 +107,20 @@ func (s *StatusBar) distributeExtraWidth(extraWidth, totalFlexWidth int) {
 	}
 }
 
+// itemText returns the text to display for an item
+func itemText(item StatusItem) string {
+	if item.Provider != nil {
+		return item.Provider.StatusText()
+	}
+	return item.Text
+}
+
 // drawItem draws a single status bar item
 func (s *StatusBar) drawItem(x int, item StatusItem) int {
-	text := item.Text
+	text := itemText(item)
 	width := item.MinWidth
 	if width == 0 {
-		width = len(text) + s.Padding*2
+		width = len([]rune(text)) + s.Padding*2
 	}
 
 	// Add padding
@@ -114,18 +129,19 @@ func (s *StatusBar) drawItem(x int, item StatusItem) int {
 	}
 
 	// Truncate or pad text to fit width
-	if len(text) > width {
-		text = text[:width]
-	} else if len(text) < width {
+	runes := []rune(text)
+	if len(runes) > width {
+		runes = runes[:width]
+	} else if len(runes) < width {
+		padding := []rune(strings.Repeat(" ", width-len(runes)))
 		switch item.Alignment {
 		case AlignLeft:
-			text = text + strings.Repeat(" ", width-len(text))
+			runes = append(runes, padding...)
 		case AlignRight:
-			text = strings.Repeat(" ", width-len(text)) + text
+			runes = append(padding, runes...)
 		case AlignCenter:
-			leftPad := (width - len(text)) / 2
-			rightPad := width - len(text) - leftPad
-			text = strings.Repeat(" ", leftPad) + text + strings.Repeat(" ", rightPad)
+			leftPad := len(padding) / 2
+			runes = append(append(padding[:leftPad:leftPad], runes...), padding[leftPad:]...)
 		}
 	}
 
@@ -134,7 +150,7 @@ func (s *StatusBar) drawItem(x int, item StatusItem) int {
 	if style == tcell.StyleDefault {
 		style = s.Style
 	}
-	for i, r := range text {
+	for i, r := range runes {
 		s.Screen.SetContent(x+i, s.Y, r, nil, style)
 	}
 
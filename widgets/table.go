@@ -3,7 +3,9 @@ package widgets
 import (
 	"sort"
 	"strings"
+	"unicode/utf8"
 
+	"github.com/deadjoe/termdodo/symbols"
 	"github.com/deadjoe/termdodo/theme"
 	"github.com/gdamore/tcell/v2"
 )
@@ -15,6 +17,25 @@ type Column struct {
 	MinWidth  int
 	MaxWidth  int
 	Alignment Alignment // Left, Right, Center
+
+	// Type controls how values are sorted. Numeric types are right-aligned
+	// unless Alignment is set to something other than AlignLeft.
+	Type ColumnType
+	// Less, when set, overrides the ordering implied by Type
+	Less func(a, b string) bool
+	// TimeLayout is the time.Parse layout used by ColumnTime columns
+	TimeLayout string
+
+	// Hidden columns are not drawn
+	Hidden bool
+	// FixedWidth, when positive, overrides the automatic width. Resizing a
+	// column sets it.
+	FixedWidth int
+	// Aggregate, when set, summarizes the column in the footer row, e.g.
+	// AggregateSum
+	Aggregate AggregateFunc
+	// Editable columns can be edited in place, see EditCell
+	Editable bool
 }
 
 // Alignment represents text alignment in table cells
@@ -30,6 +51,16 @@ const (
 	AlignRight
 )
 
+// RowRule styles every row for which Match returns true
+type RowRule struct {
+	Match func(row []string) bool
+	Style tcell.Style
+}
+
+// CellStyleFunc returns the style for the cell at the given display row and
+// column. Returning false keeps the style the table would otherwise use.
+type CellStyleFunc func(row, col int, value string) (tcell.Style, bool)
+
 // Table represents a table widget
 type Table struct {
 	X, Y          int
@@ -38,19 +69,84 @@ type Table struct {
 	Style         tcell.Style
 	HeaderStyle   tcell.Style
 	SelectedStyle tcell.Style
+	ZebraStyle    tcell.Style
+	// MatchStyle highlights text matched by the filter or search query
+	MatchStyle       tcell.Style
+	PromptErrorStyle tcell.Style
+	CheckedStyle     tcell.Style
+	FooterStyle      tcell.Style
+	EditStyle        tcell.Style
+
+	// RowRules are applied in order, so later rules win
+	RowRules  []RowRule
+	StyleFunc CellStyleFunc
+	Zebra     bool
 
 	Columns     []Column
 	Rows        [][]string
+	Model       TableModel // supplies the rows instead of Rows when set
 	SelectedRow int
+	// MultiSelect lets rows be checked, see GetSelectedRows
+	MultiSelect bool
+	// RowKey identifies rows across updates, see SetRowKey
+	RowKey func(row []string) string
+	// OnEdit is called with the new value when an edited cell is
+	// committed. Returning an error rejects the value and keeps the editor
+	// open.
+	OnEdit func(row []string, col int, value string) error
 
 	ShowHeader    bool
 	ShowBorder    bool
+	ShowFooter    bool // shown only if a column has an Aggregate
 	Sortable      bool
 	SortColumn    int
 	SortAscending bool
+	ThenBy        []SortKey // secondary sort keys, see SetSortKeys
 	HighlightRow  bool
 	ScrollOffset  int
 	VisibleRows   int
+
+	// FrozenColumns stay in place while the others scroll horizontally
+	FrozenColumns int
+	// ColumnOffset is the display position of the first scrollable column
+	// shown
+	ColumnOffset int
+	// CurrentColumn is the column that column key bindings act on, or -1
+	CurrentColumn int
+
+	// Filter is the query narrowing the visible rows, see SetFilter
+	Filter string
+	// Search is the query n and N jump between, see SetSearch
+	Search string
+
+	// TypeAhead jumps to the row starting with the characters typed. A
+	// prefix starts with a letter or digit not bound to another key.
+	TypeAhead bool
+	// TypeAheadColumn is the column type-ahead matches on, or -1 for the
+	// sort column
+	TypeAheadColumn int
+
+	view          []int        // source indices of the visible rows, nil for all
+	checked       map[int]bool // source indices of the checked rows
+	dirty         bool         // rows changed since they were last sorted
+	contentWidths []int        // widest value measured in each column
+	filter        *rowMatcher
+	search        *rowMatcher
+	promptMode    int
+	promptText    string
+	promptErr     error
+	order         []int // display order of Columns, nil for natural order
+	drag          columnDrag
+	anchor        rowAnchor
+	tree          []*TableNode // root nodes in tree mode
+	treeNodes     []*TableNode // node of each row in tree mode
+	treePrefix    []string     // guides drawn before each visible row
+	footer        []string     // aggregate of each column
+	footerStale   bool         // footer needs recomputing
+	edit          cellEditor
+	typeAhead     typeAhead
+	// measuredTreeColumn is the column whose width includes the guides
+	measuredTreeColumn int
 }
 
 // NewTable creates a new table widget
@@ -64,45 +160,74 @@ func NewTable(screen tcell.Screen, x, y, width, height int) *Table {
 		Style:         theme.Current.GetStyle(),
 		HeaderStyle:   theme.Current.GetAccentStyle(),
 		SelectedStyle: theme.Current.GetStyle().Reverse(true),
-		ShowHeader:    true,
-		ShowBorder:    true,
-		Sortable:      true,
-		HighlightRow:  true,
+		ZebraStyle:    theme.Current.GetStyle().Background(theme.Current.HighlightBg),
+		MatchStyle: theme.Current.GetStyle().
+			Background(theme.Current.Accent).
+			Foreground(theme.Current.HighlightFg),
+		PromptErrorStyle: theme.Current.GetStyle().Foreground(tcell.ColorRed),
+		CheckedStyle:     theme.Current.GetStyle().Foreground(theme.Current.Selected),
+		FooterStyle:      theme.Current.GetAccentStyle().Bold(true),
+		EditStyle:        theme.Current.GetStyle().Background(theme.Current.HighlightBg).Underline(true),
+		ShowHeader:       true,
+		ShowBorder:       true,
+		ShowFooter:       true,
+		Sortable:         true,
+		HighlightRow:     true,
+		CurrentColumn:    -1,
+		TypeAheadColumn:  -1,
 	}
 }
 
 // SetColumns sets the table columns
 func (t *Table) SetColumns(columns []Column) {
 	t.Columns = columns
+	t.order = nil
+	t.measureRows()
+	t.refreshFooter()
 	t.adjustColumnWidths()
 }
 
-// SetRows sets the table rows
+// SetRows sets the table rows, leaving tree mode. Checked rows are cleared
+// unless a row key is set, in which case rows with the same key stay
+// checked.
 func (t *Table) SetRows(rows [][]string) {
+	t.captureAnchor()
+	keys := t.checkedKeys()
 	t.Rows = rows
-	if t.SortColumn >= 0 {
-		t.sort()
-	}
-	t.adjustColumnWidths()
+	t.tree = nil
+	t.treeNodes = nil
+	t.checkKeys(keys)
+	t.Refresh()
 }
 
-// AddRow adds a row to the table
+// AddRow adds a row to the table, or a root node in tree mode. Added rows
+// are sorted and filtered in one batch when the table is next drawn or
+// handles an event, so loading many rows one at a time stays fast.
 func (t *Table) AddRow(row []string) {
-	t.Rows = append(t.Rows, row)
-	if t.SortColumn >= 0 {
-		t.sort()
+	if t.TreeMode() {
+		t.AddNode(nil, row)
+		return
 	}
-	t.adjustColumnWidths()
+	t.Rows = append(t.Rows, row)
+	t.measureRow(row)
+	t.dirty = true
 }
 
 // ClearRows clears all rows from the table
 func (t *Table) ClearRows() {
 	t.Rows = nil
+	t.tree = nil
+	t.treeNodes = nil
+	t.checked = nil
+	t.measureRows()
 	t.SelectedRow = 0
 	t.ScrollOffset = 0
+	t.refreshView()
 }
 
-// SetSortColumn sets the column to sort by
+// SetSortColumn sets the column to sort by. Choosing the current sort
+// column again reverses the direction; choosing another column clears the
+// secondary sort keys.
 func (t *Table) SetSortColumn(col int) {
 	if !t.Sortable {
 		return
@@ -115,21 +240,51 @@ func (t *Table) SetSortColumn(col int) {
 	} else {
 		t.SortColumn = col
 		t.SortAscending = true
+		t.ThenBy = nil
 	}
-	t.sort()
+	t.captureAnchor()
+	t.dirty = true
+	t.flush()
 }
 
-// sort sorts the table rows by the current sort column
+// sort sorts the table rows by the sort keys. Models only sort by the
+// primary key.
 func (t *Table) sort() {
 	if t.SortColumn < 0 || t.SortColumn >= len(t.Columns) {
 		return
 	}
-	sort.Slice(t.Rows, func(i, j int) bool {
-		if t.SortAscending {
-			return t.Rows[i][t.SortColumn] < t.Rows[j][t.SortColumn]
+	if t.TreeMode() {
+		keys := t.SortKeys()
+		t.sortTree(t.tree, keys, t.sortValues(keys))
+		return
+	}
+	if t.Model != nil {
+		if model, ok := t.Model.(SortableModel); ok {
+			model.Sort(t.SortColumn, t.SortAscending)
+			t.checked = nil
+			t.followEdit()
 		}
-		return t.Rows[i][t.SortColumn] > t.Rows[j][t.SortColumn]
+		return
+	}
+
+	keys := t.SortKeys()
+	values := t.sortValues(keys)
+	order := make([]int, len(t.Rows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return t.lessValues(keys, values[order[i]], values[order[j]])
 	})
+	t.applyOrder(order)
+}
+
+// cellValue returns the value of a cell, or "" if the row is too short
+func cellValue(row []string, col int) string {
+	if col < len(row) {
+		return row[col]
+	}
+	return ""
 }
 
 // adjustColumnWidths adjusts column widths to fit the table width
@@ -137,23 +292,49 @@ func (t *Table) adjustColumnWidths() {
 	if len(t.Columns) == 0 {
 		return
 	}
+	if len(t.contentWidths) != len(t.Columns) || (t.TreeMode() && t.measuredTreeColumn != t.treeColumn()) {
+		t.measureRows()
+	}
+
+	visible := 0
+	for i := range t.Columns {
+		if !t.Columns[i].Hidden {
+			visible++
+		}
+	}
+	if visible == 0 {
+		return
+	}
 
 	// Calculate available width
 	availableWidth := t.Width
 	if t.ShowBorder {
 		availableWidth -= 2 // Account for left and right borders
 	}
-	availableWidth -= len(t.Columns) - 1 // Account for column separators
+	availableWidth -= visible - 1 // Account for column separators
+	availableWidth -= t.markerOffset()
 
 	// First pass: set minimum widths and calculate total minimum width
 	totalMinWidth := 0
+	lastAuto := -1
 	for i := range t.Columns {
+		if t.Columns[i].Hidden {
+			continue
+		}
+		if t.Columns[i].FixedWidth > 0 {
+			t.Columns[i].Width = t.Columns[i].FixedWidth
+			totalMinWidth += t.Columns[i].Width
+			continue
+		}
+		lastAuto = i
+
 		// Set minimum width based on column title and content
-		minWidth := len(t.Columns[i].Title)
-		for _, row := range t.Rows {
-			if i < len(row) && len(row[i]) > minWidth {
-				minWidth = len(row[i])
-			}
+		minWidth := textWidth(t.Columns[i].Title)
+		if t.contentWidths[i] > minWidth {
+			minWidth = t.contentWidths[i]
+		}
+		if maxWidth := t.Columns[i].MaxWidth; maxWidth > 0 && minWidth > maxWidth {
+			minWidth = maxWidth
 		}
 		if t.Columns[i].MinWidth > minWidth {
 			minWidth = t.Columns[i].MinWidth
@@ -162,21 +343,27 @@ func (t *Table) adjustColumnWidths() {
 		totalMinWidth += minWidth
 	}
 
-	// If we have extra space, distribute it proportionally
-	if extraWidth := availableWidth - totalMinWidth; extraWidth > 0 {
+	// If we have extra space, distribute it proportionally among the
+	// columns that aren't fixed
+	if extraWidth := availableWidth - totalMinWidth; extraWidth > 0 && lastAuto >= 0 {
 		// Calculate total content width for proportional distribution
 		totalContentWidth := 0
 		for i := range t.Columns {
-			totalContentWidth += t.Columns[i].Width
+			if !t.Columns[i].Hidden && t.Columns[i].FixedWidth <= 0 {
+				totalContentWidth += t.Columns[i].Width
+			}
 		}
 
 		// Distribute extra width proportionally
 		remainingExtra := extraWidth
 		for i := range t.Columns {
-			if i == len(t.Columns)-1 {
+			if t.Columns[i].Hidden || t.Columns[i].FixedWidth > 0 {
+				continue
+			}
+			if i == lastAuto {
 				// Last column gets all remaining extra width
 				t.Columns[i].Width += remainingExtra
-			} else {
+			} else if totalContentWidth > 0 {
 				// Calculate proportional extra width
 				extra := (t.Columns[i].Width * extraWidth) / totalContentWidth
 				t.Columns[i].Width += extra
@@ -197,6 +384,12 @@ func (t *Table) Draw() {
 	if len(t.Columns) == 0 {
 		return
 	}
+	t.flush()
+	t.captureAnchor()
+	t.measureVisible()
+	if t.hasFooter() {
+		t.computeFooter()
+	}
 
 	// Draw border if enabled
 	startY := t.Y
@@ -213,6 +406,17 @@ func (t *Table) Draw() {
 
 	// Draw rows
 	t.drawRows(startY)
+
+	// Draw the footer below the rows and the filter or search prompt on
+	// the last line
+	y := startY + t.visibleRowCount()
+	if t.hasFooter() {
+		t.drawFooter(y)
+		y++
+	}
+	if t.promptMode != promptNone {
+		t.drawPrompt(y)
+	}
 }
 
 // drawBorder draws the table border
@@ -240,93 +444,297 @@ func (t *Table) drawBorder() {
 	}
 }
 
-// drawHeader draws the table header
-func (t *Table) drawHeader(y int) {
-	x := t.X
+// columnSpan is the on-screen placement of a drawn column
+type columnSpan struct {
+	index int // index into Columns
+	pos   int // display position
+	x     int
+	width int // drawn width, less than the column width when clipped
+}
+
+// innerBounds returns the x position and width available for columns
+func (t *Table) innerBounds() (int, int) {
 	if t.ShowBorder {
-		x++
+		return t.X + 1, t.Width - 2
 	}
+	return t.X, t.Width
+}
+
+// layoutColumns returns the columns to draw from left to right. Frozen
+// columns always come first, followed by the scrollable columns starting at
+// ColumnOffset. Hidden columns are skipped. It also reports whether columns
+// are scrolled out of view on either side.
+func (t *Table) layoutColumns() (spans []columnSpan, hiddenLeft, hiddenRight bool) {
+	left, width := t.innerBounds()
+	right := left + width
+	x := left + t.markerOffset()
+	order := t.displayOrder()
+
+	add := func(pos int) bool {
+		i := order[pos]
+		if t.Columns[i].Hidden {
+			return true
+		}
+		if x >= right {
+			return false
+		}
+		w := t.Columns[i].Width
+		if x+w > right {
+			w = right - x
+		}
+		spans = append(spans, columnSpan{index: i, pos: pos, x: x, width: w})
+		x += w + 1 // column separator
+		return w == t.Columns[i].Width
+	}
+
+	frozen := t.frozenCount()
+	for pos := 0; pos < frozen; pos++ {
+		if !add(pos) {
+			return spans, false, true
+		}
+	}
+
+	t.clampColumnOffset()
+	for pos := frozen; pos < t.ColumnOffset; pos++ {
+		if !t.Columns[order[pos]].Hidden {
+			hiddenLeft = true
+		}
+	}
+	for pos := t.ColumnOffset; pos < len(order); pos++ {
+		if !add(pos) {
+			return spans, hiddenLeft, true
+		}
+	}
+	return spans, hiddenLeft, false
+}
+
+// frozenCount returns the number of frozen columns that exist
+func (t *Table) frozenCount() int {
+	if t.FrozenColumns < 0 {
+		return 0
+	}
+	if t.FrozenColumns > len(t.Columns) {
+		return len(t.Columns)
+	}
+	return t.FrozenColumns
+}
+
+// clampColumnOffset keeps ColumnOffset on a scrollable column
+func (t *Table) clampColumnOffset() {
+	frozen := t.frozenCount()
+	if t.ColumnOffset >= len(t.Columns) {
+		t.ColumnOffset = len(t.Columns) - 1
+	}
+	if t.ColumnOffset < frozen {
+		t.ColumnOffset = frozen
+	}
+}
 
-	for i, col := range t.Columns {
-		// Draw column title
-		title := t.alignText(col.Title, col.Width, col.Alignment)
+// drawCell draws aligned text clipped to width
+func (t *Table) drawCell(x, y, width int, text string, alignment Alignment, style tcell.Style) {
+	for i, r := range []rune(t.alignText(text, width, alignment)) {
+		if i >= width {
+			break
+		}
+		t.Screen.SetContent(x+i, y, r, nil, style)
+	}
+}
+
+// drawSeparators draws the separator after each span that has room for one
+func (t *Table) drawSeparators(spans []columnSpan, y int) {
+	left, width := t.innerBounds()
+	for i, span := range spans {
+		sx := span.x + span.width
+		if i == len(spans)-1 || sx >= left+width {
+			break
+		}
+		t.Screen.SetContent(sx, y, '│', nil, t.Style)
+	}
+}
+
+// drawHeader draws the table header
+func (t *Table) drawHeader(y int) {
+	spans, hiddenLeft, hiddenRight := t.layoutColumns()
+	for _, span := range spans {
+		col := t.Columns[span.index]
+		title := col.Title
 		style := t.HeaderStyle
-		if t.Sortable && i == t.SortColumn {
-			if t.SortAscending {
-				title = title + "\u25B2" // Unicode UP TRIANGLE
-			} else {
-				title = title + "\u25BC" // Unicode DOWN TRIANGLE
-			}
+		if span.index == t.CurrentColumn {
+			style = style.Underline(true)
 		}
-		for i, r := range title {
-			t.Screen.SetContent(x+i, y, r, nil, style)
+		if indicator := t.sortIndicator(span.index); indicator != "" {
+			// Keep the indicator visible when the title is clipped
+			room := span.width - textWidth(indicator)
+			if room < 0 {
+				room = 0
+			}
+			if runes := []rune(title); len(runes) > room {
+				title = string(runes[:room])
+			}
+			title += indicator
 		}
+		t.drawCell(span.x, y, span.width, title, col.alignment(), style)
+	}
+	t.drawSeparators(spans, y)
 
-		// Draw column separator
-		if i < len(t.Columns)-1 {
-			t.Screen.SetContent(x+col.Width, y, '│', nil, t.Style)
-			x += col.Width + 1
+	// Show which side has more columns to scroll to
+	if hiddenLeft {
+		for _, span := range spans {
+			if span.pos >= t.frozenCount() {
+				t.Screen.SetContent(span.x, y, symbols.ArrowLeft, nil, t.HeaderStyle)
+				break
+			}
 		}
 	}
+	if hiddenRight {
+		left, width := t.innerBounds()
+		t.Screen.SetContent(left+width-1, y, symbols.ArrowRight, nil, t.HeaderStyle)
+	}
 }
 
-// drawRows draws the table rows
-func (t *Table) drawRows(startY int) {
-	visibleRows := t.Height - startY
+// visibleRowCount returns the number of rows that fit between the header
+// and the footer
+func (t *Table) visibleRowCount() int {
+	rows := t.Height
 	if t.ShowBorder {
-		visibleRows--
+		rows -= 2
+	}
+	if t.ShowHeader {
+		rows--
+	}
+	if t.hasFooter() {
+		rows--
+	}
+	if t.promptMode != promptNone {
+		rows--
+	}
+	if rows < 0 {
+		rows = 0
 	}
+	return rows
+}
+
+// drawRows draws the table rows
+func (t *Table) drawRows(startY int) {
+	t.VisibleRows = t.visibleRowCount()
+	spans, _, _ := t.layoutColumns()
+
+	matcher := t.highlighter()
 
-	maxRow := len(t.Rows)
-	if t.ScrollOffset+visibleRows < maxRow {
-		maxRow = t.ScrollOffset + visibleRows
+	maxRow := t.rowCount()
+	if t.ScrollOffset+t.VisibleRows < maxRow {
+		maxRow = t.ScrollOffset + t.VisibleRows
 	}
 
+	editRow := t.editRow()
 	for rowIdx := t.ScrollOffset; rowIdx < maxRow; rowIdx++ {
 		y := startY + rowIdx - t.ScrollOffset
-		x := t.X
-		if t.ShowBorder {
-			x++
-		}
-
-		row := t.Rows[rowIdx]
-		style := t.Style
-		if t.HighlightRow && rowIdx == t.SelectedRow {
-			style = t.SelectedStyle
+		row := t.displayRow(rowIdx)
+		selected := t.HighlightRow && rowIdx == t.SelectedRow
+		rowStyle := t.rowStyle(rowIdx, row)
+		if t.MultiSelect {
+			markerStyle := rowStyle
+			if selected {
+				markerStyle = t.SelectedStyle
+			}
+			t.drawMarker(rowIdx, y, markerStyle)
 		}
 
-		// Draw each cell in the row
-		for i, col := range t.Columns {
-			cellText := ""
-			if i < len(row) {
-				cellText = t.alignText(row[i], col.Width, col.Alignment)
+		// Draw each visible cell in the row
+		for _, span := range spans {
+			style := t.SelectedStyle
+			if !selected {
+				style = t.cellStyle(rowIdx, span.index, row, rowStyle)
 			}
-
-			// Draw cell content
-			for i, r := range cellText {
-				t.Screen.SetContent(x+i, y, r, nil, style)
+			col := t.Columns[span.index]
+			value := cellValue(row, span.index)
+			alignment := col.alignment()
+			var ranges [][2]int
+			if matcher != nil {
+				ranges = matcher.ranges(span.index, value)
 			}
-			// Fill remaining space in cell
-			for i := len(cellText); i < col.Width; i++ {
-				t.Screen.SetContent(x+i, y, ' ', nil, style)
+			if prefix := t.rowPrefix(rowIdx, span.index); prefix != "" {
+				// Tree guides come first, so the value follows them
+				shift := textWidth(prefix)
+				for i := range ranges {
+					ranges[i][0] += shift
+					ranges[i][1] += shift
+				}
+				value = prefix + value
+				alignment = AlignLeft
 			}
+			if rowIdx == editRow && span.index == t.edit.col {
+				t.drawEditor(span, y)
+				continue
+			}
+			t.drawCell(span.x, y, span.width, value, alignment, style)
+			t.drawMatches(span, y, value, alignment, ranges)
+		}
+		t.drawSeparators(spans, y)
+	}
+}
 
-			// Draw column separator
-			if i < len(t.Columns)-1 {
-				t.Screen.SetContent(x+col.Width, y, '│', nil, t.Style)
-				x += col.Width + 1
+// drawMatches redraws the matched parts of a cell in MatchStyle. Like
+// drawCell, it aligns the value within the span, which is narrower than
+// the column when the column is clipped at the table edge.
+func (t *Table) drawMatches(span columnSpan, y int, value string, alignment Alignment, ranges [][2]int) {
+	if len(ranges) == 0 {
+		return
+	}
+	runes := []rune(value)
+	offset := 0
+	if padding := span.width - len(runes); padding > 0 {
+		switch alignment {
+		case AlignRight:
+			offset = padding
+		case AlignCenter:
+			offset = padding / 2
+		}
+	}
+	for _, r := range ranges {
+		for i := r[0]; i < r[1]; i++ {
+			if x := offset + i; x < span.width {
+				t.Screen.SetContent(span.x+x, y, runes[i], nil, t.MatchStyle)
 			}
 		}
 	}
 }
 
+// rowStyle returns the style for a row before per-cell styling
+func (t *Table) rowStyle(rowIdx int, row []string) tcell.Style {
+	style := t.Style
+	if t.Zebra && rowIdx%2 == 1 {
+		style = t.ZebraStyle
+	}
+	for _, rule := range t.RowRules {
+		if rule.Match != nil && rule.Match(row) {
+			style = rule.Style
+		}
+	}
+	if t.IsRowChecked(rowIdx) {
+		style = t.CheckedStyle
+	}
+	return style
+}
+
+// cellStyle returns the style for a single cell
+func (t *Table) cellStyle(rowIdx, col int, row []string, rowStyle tcell.Style) tcell.Style {
+	if t.StyleFunc != nil {
+		if style, ok := t.StyleFunc(rowIdx, col, cellValue(row, col)); ok {
+			return style
+		}
+	}
+	return rowStyle
+}
+
 // alignText aligns text within the given width
 func (t *Table) alignText(text string, width int, alignment Alignment) string {
-	if len(text) > width {
-		return text[:width]
+	runes := []rune(text)
+	if len(runes) > width {
+		return string(runes[:width])
 	}
 
-	padding := width - len(text)
+	padding := width - len(runes)
 	switch alignment {
 	case AlignLeft:
 		return text + strings.Repeat(" ", padding)
@@ -341,14 +749,16 @@ func (t *Table) alignText(text string, width int, alignment Alignment) string {
 	}
 }
 
+// textWidth returns the number of cells needed to draw text
+func textWidth(text string) int {
+	return utf8.RuneCountInString(text)
+}
+
 // handleUpKey handles up arrow key event
 func (t *Table) handleUpKey() bool {
 	if t.SelectedRow > 0 {
 		t.SelectedRow--
-		// Scroll up if selected row is above visible area
-		if t.SelectedRow < t.ScrollOffset {
-			t.ScrollOffset = t.SelectedRow
-		}
+		t.scrollToSelected()
 		return true
 	}
 	return false
@@ -356,21 +766,27 @@ func (t *Table) handleUpKey() bool {
 
 // handleDownKey handles down arrow key event
 func (t *Table) handleDownKey() bool {
-	if t.SelectedRow < len(t.Rows)-1 {
+	if t.SelectedRow < t.rowCount()-1 {
 		t.SelectedRow++
-		// Scroll down if selected row is below visible area
-		visibleRows := t.Height - 2 // Account for header and border
-		if t.SelectedRow >= t.ScrollOffset+visibleRows {
-			t.ScrollOffset = t.SelectedRow - visibleRows + 1
-		}
+		t.scrollToSelected()
 		return true
 	}
 	return false
 }
 
+// scrollToSelected scrolls the least amount needed to show the selected row
+func (t *Table) scrollToSelected() {
+	if t.SelectedRow < t.ScrollOffset {
+		t.ScrollOffset = t.SelectedRow
+	}
+	if visibleRows := t.visibleRowCount(); t.SelectedRow >= t.ScrollOffset+visibleRows {
+		t.ScrollOffset = t.SelectedRow - visibleRows + 1
+	}
+}
+
 // handlePageUpKey handles page up key event
 func (t *Table) handlePageUpKey() bool {
-	visibleRows := t.Height - 2 // Account for header and border
+	visibleRows := t.visibleRowCount()
 	t.ScrollOffset -= visibleRows
 	if t.ScrollOffset < 0 {
 		t.ScrollOffset = 0
@@ -383,8 +799,8 @@ func (t *Table) handlePageUpKey() bool {
 
 // handlePageDownKey handles page down key event
 func (t *Table) handlePageDownKey() bool {
-	visibleRows := t.Height - 2 // Account for header and border
-	maxScroll := len(t.Rows) - visibleRows
+	visibleRows := t.visibleRowCount()
+	maxScroll := t.rowCount() - visibleRows
 	if maxScroll < 0 {
 		maxScroll = 0
 	}
@@ -407,20 +823,88 @@ func (t *Table) handleHomeKey() bool {
 
 // handleEndKey handles end key event
 func (t *Table) handleEndKey() bool {
-	t.SelectedRow = len(t.Rows) - 1
-	visibleRows := t.Height - 2
-	t.ScrollOffset = len(t.Rows) - visibleRows
+	t.SelectedRow = t.rowCount() - 1
+	visibleRows := t.visibleRowCount()
+	t.ScrollOffset = t.rowCount() - visibleRows
 	if t.ScrollOffset < 0 {
 		t.ScrollOffset = 0
 	}
 	return true
 }
 
-// HandleEvent handles keyboard events
+// HandleEvent handles keyboard events. Pressing / opens a prompt that
+// filters rows as you type, ? searches without hiding rows, and n and N
+// jump between search matches while no filter is active. With MultiSelect
+// enabled, Space, Shift+Up/Down, Ctrl+A and * check rows. Enter or F2
+// edits a cell of an editable column. See handleColumnKey and
+// handleSortKey for the keys that rearrange and sort columns. With
+// TypeAhead enabled, letters and digits not bound to these keys jump to
+// the next row starting with what was typed; once a prefix is started,
+// every character continues it while rows match.
 func (t *Table) HandleEvent(ev *tcell.EventKey) bool {
-	if len(t.Rows) == 0 {
+	t.flush()
+	defer t.captureAnchor()
+	if t.edit.active {
+		return t.handleEditorKey(ev)
+	}
+	if t.promptMode != promptNone {
+		return t.handlePromptKey(ev)
+	}
+	if t.handleTypeAhead(ev) {
+		return true
+	}
+
+	switch ev.Key() {
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case '/':
+			t.openPrompt(promptFilter)
+			return true
+		case '?':
+			t.openPrompt(promptSearch)
+			return true
+		case 'n':
+			if !t.Filtered() && t.NextMatch() {
+				return true
+			}
+		case 'N':
+			if !t.Filtered() && t.PreviousMatch() {
+				return true
+			}
+		}
+	case tcell.KeyEscape:
+		if t.Filtered() {
+			t.ClearFilter()
+			return true
+		}
+	}
+
+	if t.handleColumnKey(ev) {
+		return true
+	}
+	if t.handleSortKey(ev) {
+		return true
+	}
+
+	if t.rowCount() == 0 {
+		// Columns still scroll, as the header shows them
+		switch ev.Key() {
+		case tcell.KeyLeft:
+			return t.ScrollColumns(-1)
+		case tcell.KeyRight:
+			return t.ScrollColumns(1)
+		}
 		return false
 	}
+	if t.handleEditKey(ev) {
+		return true
+	}
+	if t.MultiSelect && t.handleSelectKey(ev) {
+		return true
+	}
+	if t.TreeMode() && t.handleTreeKey(ev) {
+		return true
+	}
 
 	switch ev.Key() {
 	case tcell.KeyUp:
@@ -435,15 +919,59 @@ func (t *Table) HandleEvent(ev *tcell.EventKey) bool {
 		return t.handleHomeKey()
 	case tcell.KeyEnd:
 		return t.handleEndKey()
+	case tcell.KeyLeft:
+		return t.ScrollColumns(-1)
+	case tcell.KeyRight:
+		return t.ScrollColumns(1)
 	}
 
 	return false
 }
 
-// GetSelectedRow returns the currently selected row
+// ScrollColumns scrolls the non-frozen columns by delta columns, skipping
+// hidden ones. It returns false if the table can't scroll further in that
+// direction.
+func (t *Table) ScrollColumns(delta int) bool {
+	_, hiddenLeft, hiddenRight := t.layoutColumns()
+	step := 1
+	switch {
+	case delta < 0 && hiddenLeft:
+		step = -1
+	case delta > 0 && hiddenRight:
+	default:
+		return false
+	}
+
+	order := t.displayOrder()
+	for n := delta * step; n > 0; n-- {
+		pos := t.ColumnOffset + step
+		for pos >= t.frozenCount() && pos < len(order) && t.Columns[order[pos]].Hidden {
+			pos += step
+		}
+		if pos < t.frozenCount() || pos >= len(order) {
+			break
+		}
+		t.ColumnOffset = pos
+	}
+	t.clampColumnOffset()
+	return true
+}
+
+// SetFrozenColumns sets the number of leading columns that stay in place
+// while scrolling horizontally
+func (t *Table) SetFrozenColumns(n int) {
+	if n < 0 {
+		n = 0
+	}
+	t.FrozenColumns = n
+	t.clampColumnOffset()
+}
+
+// GetSelectedRow returns the currently selected row and its index among
+// the visible rows
 func (t *Table) GetSelectedRow() ([]string, int) {
-	if t.SelectedRow >= 0 && t.SelectedRow < len(t.Rows) {
-		return t.Rows[t.SelectedRow], t.SelectedRow
+	if t.SelectedRow >= 0 && t.SelectedRow < t.rowCount() {
+		return t.displayRow(t.SelectedRow), t.SelectedRow
 	}
 	return nil, -1
 }
@@ -458,11 +986,61 @@ func (t *Table) SetShowHeader(show bool) {
 	t.ShowHeader = show
 }
 
+// SetShowFooter sets whether to show the aggregate footer row
+func (t *Table) SetShowFooter(show bool) {
+	t.ShowFooter = show
+}
+
 // SetShowBorder sets whether to show the border
 func (t *Table) SetShowBorder(show bool) {
 	t.ShowBorder = show
 }
 
+// SetZebra sets whether to shade every other row with ZebraStyle
+func (t *Table) SetZebra(enabled bool) {
+	t.Zebra = enabled
+}
+
+// SetStyleFunc sets the hook that styles individual cells
+func (t *Table) SetStyleFunc(fn CellStyleFunc) {
+	t.StyleFunc = fn
+}
+
+// AddRowRule adds a rule that styles matching rows
+func (t *Table) AddRowRule(match func(row []string) bool, style tcell.Style) {
+	t.RowRules = append(t.RowRules, RowRule{Match: match, Style: style})
+}
+
+// ClearRowRules removes all row rules
+func (t *Table) ClearRowRules() {
+	t.RowRules = nil
+}
+
+// ColumnAbove returns a row matcher for rows whose numeric value in col is
+// greater than threshold, e.g. ColumnAbove(2, 90) for CPU above 90%
+func ColumnAbove(col int, threshold float64) func(row []string) bool {
+	return func(row []string) bool {
+		v, ok := parseFloat(cellValue(row, col))
+		return ok && v > threshold
+	}
+}
+
+// ColumnBelow returns a row matcher for rows whose numeric value in col is
+// less than threshold
+func ColumnBelow(col int, threshold float64) func(row []string) bool {
+	return func(row []string) bool {
+		v, ok := parseFloat(cellValue(row, col))
+		return ok && v < threshold
+	}
+}
+
+// ColumnEquals returns a row matcher for rows whose value in col is value
+func ColumnEquals(col int, value string) func(row []string) bool {
+	return func(row []string) bool {
+		return cellValue(row, col) == value
+	}
+}
+
 // SetSortable sets whether the table is sortable
 func (t *Table) SetSortable(sortable bool) {
 	t.Sortable = sortable
@@ -83,3 +83,166 @@ func TestTableSetRows(t *testing.T) {
 		}
 	}
 }
+
+func TestTableCellStyles(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(40, 10)
+
+	base := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	zebra := tcell.StyleDefault.Background(tcell.ColorGray)
+	hot := tcell.StyleDefault.Foreground(tcell.ColorRed)
+	bold := tcell.StyleDefault.Bold(true)
+
+	table := NewTable(screen, 0, 0, 20, 6)
+	table.SetShowBorder(false)
+	table.SetHighlightRow(false)
+	table.Style = base
+	table.ZebraStyle = zebra
+	table.SetColumns([]Column{{Title: "Name"}, {Title: "CPU", Type: ColumnFloat}})
+	table.SortColumn = -1
+	table.SetRows([][]string{
+		{"idle", "1.0"},
+		{"busy", "95.0"},
+		{"calm", "2.0"},
+		{"kworker", "3.0"},
+	})
+	table.SetZebra(true)
+	table.AddRowRule(ColumnAbove(1, 90), hot)
+	table.SetStyleFunc(func(row, col int, value string) (tcell.Style, bool) {
+		if col == 0 && value == "kworker" {
+			return bold, true
+		}
+		return tcell.Style{}, false
+	})
+	table.Draw()
+
+	styleAt := func(x, y int) tcell.Style {
+		_, _, style, _ := screen.GetContent(x, y)
+		return style
+	}
+
+	if styleAt(0, 1) != base {
+		t.Error("First row should use the base style")
+	}
+	if styleAt(0, 2) != hot {
+		t.Error("Row matching the rule should use the rule style over zebra")
+	}
+	if styleAt(0, 3) != base {
+		t.Error("Third row should use the base style")
+	}
+	if styleAt(0, 4) != bold {
+		t.Error("StyleFunc should style the kworker cell")
+	}
+	if styleAt(15, 4) != zebra {
+		t.Error("Other cells of the fourth row should use the zebra style")
+	}
+
+	table.SetHighlightRow(true)
+	table.SelectedRow = 1
+	table.Draw()
+	if styleAt(0, 2) != table.SelectedStyle {
+		t.Error("Selected row should use SelectedStyle over row rules")
+	}
+}
+
+func TestTableHorizontalScroll(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(40, 10)
+
+	table := NewTable(screen, 0, 0, 14, 4)
+	table.SetShowBorder(false)
+	table.SortColumn = -1
+	table.SetColumns([]Column{
+		{Title: "PID", MinWidth: 3},
+		{Title: "AAAA", MinWidth: 4},
+		{Title: "BBBB", MinWidth: 4},
+		{Title: "CCCC", MinWidth: 4},
+	})
+	table.SetRows([][]string{{"1", "a", "b", "c"}})
+	table.SetFrozenColumns(1)
+
+	spans, hiddenLeft, hiddenRight := table.layoutColumns()
+	if hiddenLeft || !hiddenRight {
+		t.Errorf("Expected only columns on the right to be hidden, got left=%v right=%v", hiddenLeft, hiddenRight)
+	}
+	if spans[0].index != 0 || spans[1].index != 1 {
+		t.Errorf("Expected PID followed by AAAA, got %+v", spans)
+	}
+
+	right := tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone)
+	if !table.HandleEvent(right) {
+		t.Fatal("Right should scroll when columns are hidden")
+	}
+	spans, hiddenLeft, _ = table.layoutColumns()
+	if spans[0].index != 0 || spans[1].index != 2 {
+		t.Errorf("Expected PID to stay frozen followed by BBBB, got %+v", spans)
+	}
+	if !hiddenLeft {
+		t.Error("Expected columns on the left to be hidden after scrolling")
+	}
+
+	for table.HandleEvent(right) {
+	}
+	_, _, hiddenRight = table.layoutColumns()
+	if hiddenRight {
+		t.Error("Expected to reach the last column")
+	}
+
+	table.Draw()
+	if r, _, _, _ := screen.GetContent(4, 0); r != '←' {
+		t.Errorf("Expected left scroll indicator, got %q", r)
+	}
+	if got := readRow(screen, 0, 1, 3); got != "1  " {
+		t.Errorf("Expected frozen PID cell, got %q", got)
+	}
+
+	left := tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone)
+	for table.HandleEvent(left) {
+	}
+	if table.ColumnOffset != 1 {
+		t.Errorf("Expected to scroll back to the first scrollable column, got %d", table.ColumnOffset)
+	}
+}
+
+func TestTableHorizontalScrollWithoutRows(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(40, 10)
+
+	table := NewTable(screen, 0, 0, 14, 4)
+	table.SetShowBorder(false)
+	table.SortColumn = -1
+	table.SetColumns([]Column{
+		{Title: "AAAA", MinWidth: 4},
+		{Title: "BBBB", MinWidth: 4},
+		{Title: "CCCC", MinWidth: 4},
+		{Title: "DDDD", MinWidth: 4},
+	})
+
+	if !table.HandleEvent(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone)) {
+		t.Fatal("Right should scroll the header of an empty table")
+	}
+	if table.ColumnOffset != 1 {
+		t.Errorf("Expected column offset 1, got %d", table.ColumnOffset)
+	}
+	if !table.HandleEvent(tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone)) {
+		t.Fatal("Left should scroll back")
+	}
+	if table.ColumnOffset != 0 {
+		t.Errorf("Expected column offset 0, got %d", table.ColumnOffset)
+	}
+	if table.HandleEvent(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)) {
+		t.Error("Down should not be handled without rows")
+	}
+}
@@ -0,0 +1,263 @@
+package widgets
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AggregateFunc summarizes the values of a column for the footer row.
+// values holds the column's value in each row passing the filter; in tree
+// mode, only leaf rows are included, whether expanded or not, since
+// parent rows usually summarize their children.
+type AggregateFunc func(col Column, values []string) string
+
+// AggregateModel is a TableModel that computes the footer itself, e.g. by
+// issuing a SUM query, so the table doesn't read every row for it. It is
+// used while no filter is set; filtered footers are computed from the
+// rows the filter kept.
+type AggregateModel interface {
+	TableModel
+	// Aggregate returns the footer value of column col over all rows
+	Aggregate(col int) string
+}
+
+// AggregateSum adds up the values that parse as the column type
+func AggregateSum(col Column, values []string) string {
+	sum, n := 0.0, 0
+	for _, value := range values {
+		if v, ok := col.parse(value); ok {
+			sum += v
+			n++
+		}
+	}
+	if n == 0 {
+		return ""
+	}
+	return formatAggregate(col, sum, values)
+}
+
+// AggregateAvg averages the values that parse as the column type
+func AggregateAvg(col Column, values []string) string {
+	sum, n := 0.0, 0
+	for _, value := range values {
+		if v, ok := col.parse(value); ok {
+			sum += v
+			n++
+		}
+	}
+	if n == 0 {
+		return ""
+	}
+	if col.Type == ColumnInt {
+		col.Type = ColumnFloat // averages of integers have decimals
+	}
+	return formatAggregate(col, sum/float64(n), values)
+}
+
+// AggregateMin returns the value that sorts first in the column
+func AggregateMin(col Column, values []string) string {
+	return extreme(col, values, -1)
+}
+
+// AggregateMax returns the value that sorts last in the column
+func AggregateMax(col Column, values []string) string {
+	return extreme(col, values, 1)
+}
+
+// AggregateCount counts the values that aren't empty
+func AggregateCount(col Column, values []string) string {
+	n := 0
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			n++
+		}
+	}
+	return strconv.Itoa(n)
+}
+
+// AggregateLabel returns an aggregate that always shows text, e.g. "Total"
+// in the first column
+func AggregateLabel(text string) AggregateFunc {
+	return func(Column, []string) string {
+		return text
+	}
+}
+
+// extreme returns the non-empty value comparing lowest (sign < 0) or
+// highest (sign > 0) in the column
+func extreme(col Column, values []string, sign int) string {
+	result := ""
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		if result == "" || col.compare(value, result)*sign > 0 {
+			result = value
+		}
+	}
+	return result
+}
+
+// formatAggregate formats a computed value like the values of the column
+func formatAggregate(col Column, v float64, values []string) string {
+	switch col.Type {
+	case ColumnInt:
+		return strconv.FormatInt(int64(v), 10)
+	case ColumnBytes:
+		return formatBytes(v)
+	case ColumnDuration:
+		return formatDuration(time.Duration(v))
+	}
+	text := strconv.FormatFloat(v, 'f', 2, 64)
+	text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	for _, value := range values {
+		if strings.HasSuffix(strings.TrimSpace(value), "%") {
+			return text + "%"
+		}
+	}
+	return text
+}
+
+// formatBytes formats a size with a K/M/G/T suffix, in powers of 1024
+func formatBytes(v float64) string {
+	units := []string{"", "K", "M", "G", "T", "P"}
+	i := 0
+	for v >= 1024 && i < len(units)-1 {
+		v /= 1024
+		i++
+	}
+	if i == 0 {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%.1f%s", v, units[i])
+}
+
+// SetColumnAggregate sets the aggregate shown in the footer below col.
+// Pass nil to remove it.
+func (t *Table) SetColumnAggregate(col int, fn AggregateFunc) {
+	if col < 0 || col >= len(t.Columns) {
+		return
+	}
+	t.Columns[col].Aggregate = fn
+	t.refreshFooter()
+	t.adjustColumnWidths()
+}
+
+// hasFooter returns whether any column has an aggregate to show
+func (t *Table) hasFooter() bool {
+	if !t.ShowFooter {
+		return false
+	}
+	for _, col := range t.Columns {
+		if col.Aggregate != nil && !col.Hidden {
+			return true
+		}
+	}
+	return false
+}
+
+// Footer returns the aggregate of each column over the rows passing the
+// filter, or "" for columns without one
+func (t *Table) Footer() []string {
+	t.flush()
+	t.computeFooter()
+	return t.footer
+}
+
+// refreshFooter marks the aggregates for recomputing when the footer is
+// next drawn or read, so models are only read for a footer that is shown
+func (t *Table) refreshFooter() {
+	t.footerStale = true
+}
+
+// computeFooter recomputes stale aggregates, widening columns to fit them
+func (t *Table) computeFooter() {
+	if !t.footerStale {
+		return
+	}
+	t.footerStale = false
+	t.footer = nil
+	aggregated := false
+	for _, col := range t.Columns {
+		if col.Aggregate != nil {
+			aggregated = true
+		}
+	}
+	if !aggregated {
+		return
+	}
+
+	model, modelAggregates := t.Model.(AggregateModel)
+	modelAggregates = modelAggregates && t.filter == nil
+	var rows [][]string
+	if !modelAggregates {
+		rows = t.aggregateRows()
+	}
+
+	grew := false
+	t.footer = make([]string, len(t.Columns))
+	values := make([]string, len(rows))
+	for i, col := range t.Columns {
+		if col.Aggregate == nil {
+			continue
+		}
+		if modelAggregates {
+			t.footer[i] = model.Aggregate(i)
+		} else {
+			for j, row := range rows {
+				values[j] = cellValue(row, i)
+			}
+			t.footer[i] = col.Aggregate(col, values)
+		}
+		if i < len(t.contentWidths) && textWidth(t.footer[i]) > t.contentWidths[i] {
+			t.contentWidths[i] = textWidth(t.footer[i])
+			grew = true
+		}
+	}
+	if grew {
+		t.adjustColumnWidths()
+	}
+}
+
+// aggregateRows returns the rows the footer summarizes: the rows passing
+// the filter, or in tree mode the leaf rows passing it
+func (t *Table) aggregateRows() [][]string {
+	if !t.TreeMode() {
+		count := t.rowCount()
+		rows := make([][]string, count)
+		for i := range rows {
+			rows[i] = t.displayRow(i)
+		}
+		return rows
+	}
+
+	var rows [][]string
+	var walk func(nodes []*TableNode)
+	walk = func(nodes []*TableNode) {
+		for _, node := range nodes {
+			if len(node.Children) > 0 {
+				walk(node.Children)
+			} else if t.filter == nil || t.filter.matchRow(node.Cells) {
+				rows = append(rows, node.Cells)
+			}
+		}
+	}
+	walk(t.tree)
+	return rows
+}
+
+// drawFooter draws the aggregate row
+func (t *Table) drawFooter(y int) {
+	spans, _, _ := t.layoutColumns()
+	left, width := t.innerBounds()
+	for x := left; x < left+width; x++ {
+		t.Screen.SetContent(x, y, ' ', nil, t.FooterStyle)
+	}
+	for _, span := range spans {
+		t.drawCell(span.x, y, span.width, cellValue(t.footer, span.index),
+			t.Columns[span.index].alignment(), t.FooterStyle)
+	}
+	t.drawSeparators(spans, y)
+}
@@ -0,0 +1,152 @@
+package widgets
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestAggregates(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		fn     AggregateFunc
+		col    Column
+		values []string
+		want   string
+	}{
+		{"sum int", AggregateSum, Column{Type: ColumnInt}, []string{"1,000", "24", "n/a"}, "1024"},
+		{"sum percent", AggregateSum, Column{Type: ColumnFloat}, []string{"12.5%", "0.25%"}, "12.75%"},
+		{"sum bytes", AggregateSum, Column{Type: ColumnBytes}, []string{"512M", "1.5G"}, "2.0G"},
+		{"sum duration", AggregateSum, Column{Type: ColumnDuration}, []string{"1m30s", "01:00"}, "2:30"},
+		{"sum nothing", AggregateSum, Column{Type: ColumnInt}, []string{"", "-"}, ""},
+		{"avg int", AggregateAvg, Column{Type: ColumnInt}, []string{"1", "2"}, "1.5"},
+		{"min", AggregateMin, Column{Type: ColumnBytes}, []string{"2K", "", "900"}, "900"},
+		{"max", AggregateMax, Column{}, []string{"alice", "root", "bob"}, "root"},
+		{"count", AggregateCount, Column{}, []string{"a", " ", "b"}, "2"},
+		{"label", AggregateLabel("Total"), Column{}, nil, "Total"},
+	}
+	for _, tt := range tests {
+		if got := tt.fn(tt.col, tt.values); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestTableFooter(t *testing.T) {
+	t.Parallel()
+	table, screen := newProcessTable(t)
+	table.SetColumnAggregate(0, AggregateSum)
+	table.SetColumnAggregate(1, AggregateCount)
+	table.Height = 5 // border, header, two rows and the footer don't fit
+
+	if got := table.Footer(); got[0] != "1003" || got[1] != "5" || got[2] != "" {
+		t.Errorf("Expected footer totals, got %q", got)
+	}
+	if table.visibleRowCount() != 1 {
+		t.Errorf("Expected the footer to take a row, got %d rows", table.visibleRowCount())
+	}
+
+	// The footer stays on the last line while the body scrolls
+	table.handleEndKey()
+	table.Draw()
+	if got := readRow(screen, 1, 3, 28); !strings.Contains(got, "1003") {
+		t.Errorf("Expected the footer below the rows, got %q", got)
+	}
+	if _, _, style, _ := screen.GetContent(1, 3); style != table.FooterStyle {
+		t.Error("Expected the footer drawn in FooterStyle")
+	}
+
+	// Filtering and row changes recalculate it
+	if err := table.SetFilter("nginx"); err != nil {
+		t.Fatal(err)
+	}
+	if got := table.Footer(); got[0] != "401" || got[1] != "2" {
+		t.Errorf("Expected totals of the filtered rows, got %q", got)
+	}
+	table.ClearFilter()
+	table.AddRow([]string{"400", "bob", "cron"})
+	if got := table.Footer(); got[0] != "1403" || got[1] != "6" {
+		t.Errorf("Expected totals to include the added row, got %q", got)
+	}
+
+	table.SetShowFooter(false)
+	if table.hasFooter() || table.visibleRowCount() != 2 {
+		t.Error("Expected no footer when hidden")
+	}
+}
+
+func TestTableTreeFooter(t *testing.T) {
+	t.Parallel()
+	table, _ := newProcessTree(t)
+	table.SetColumnAggregate(1, AggregateSum)
+
+	// Only leaves count, so parents don't add their children twice and
+	// collapsing a node leaves the totals alone
+	if got := table.Footer()[1]; got != "511" {
+		t.Errorf("Expected the sum of the leaf rows, got %q", got)
+	}
+	table.CollapseAll()
+	if got := table.Footer()[1]; got != "511" {
+		t.Errorf("Expected collapsing to keep the totals, got %q", got)
+	}
+
+	if err := table.SetFilter("bash"); err != nil {
+		t.Fatal(err)
+	}
+	if got := table.Footer()[1]; got != "310" {
+		t.Errorf("Expected the sum of the matching leaves, got %q", got)
+	}
+}
+
+// aggregateModel sums its first column itself
+type aggregateModel struct {
+	countingModel
+	aggregated int
+}
+
+func (m *aggregateModel) Aggregate(col int) string {
+	m.aggregated++
+	return "total"
+}
+
+func TestTableModelFooter(t *testing.T) {
+	t.Parallel()
+	screen := createTestScreen(t)
+	table := NewTable(screen, 0, 0, 30, 10)
+	table.SetColumns([]Column{{Title: "N", Type: ColumnInt}, {Title: "Text"}})
+	model := &aggregateModel{countingModel: countingModel{count: 1000, reads: map[int]bool{}, ascending: true}}
+	table.SetModel(model)
+
+	// Aggregates are only computed once the footer is needed
+	table.SetShowFooter(false)
+	table.SetColumnAggregate(0, AggregateSum)
+	table.Draw()
+	if model.aggregated != 0 || len(model.reads) > table.visibleRowCount() {
+		t.Errorf("Expected a hidden footer not to be computed, read %d rows", len(model.reads))
+	}
+
+	// The model supplies them instead of every row being read
+	table.SetShowFooter(true)
+	table.Draw()
+	if got := table.Footer()[0]; got != "total" || model.aggregated != 1 {
+		t.Errorf("Expected the model's aggregate, got %q", got)
+	}
+	if len(model.reads) > 10 {
+		t.Errorf("Expected only drawn rows read, read %d", len(model.reads))
+	}
+
+	// Filtered footers come from the rows the filter kept
+	if err := table.SetFilter("99"); err != nil {
+		t.Fatal(err)
+	}
+	sum := 0
+	for n := 0; n < model.count; n++ {
+		if strings.Contains(strconv.Itoa(n), "99") {
+			sum += n
+		}
+	}
+	if got := table.Footer()[0]; got != strconv.Itoa(sum) {
+		t.Errorf("Expected the sum of the filtered rows %d, got %q", sum, got)
+	}
+}
@@ -0,0 +1,241 @@
+package widgets
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnType describes how the values of a table column are compared
+type ColumnType int
+
+// Column types
+const (
+	// ColumnString compares values as plain text
+	ColumnString ColumnType = iota
+	// ColumnInt compares values as integers, e.g. "42" or "1,024"
+	ColumnInt
+	// ColumnFloat compares values as decimal numbers, e.g. "3.5" or "12.5%"
+	ColumnFloat
+	// ColumnBytes compares sizes with unit suffixes, e.g. "200M" or "1.5 GiB"
+	ColumnBytes
+	// ColumnDuration compares durations, e.g. "1h30m", "05:12" or "2-03:04:05"
+	ColumnDuration
+	// ColumnTime compares timestamps parsed with the column's TimeLayout
+	ColumnTime
+)
+
+// defaultTimeLayouts are tried in order when a time column has no layout
+var defaultTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"15:04:05",
+	"15:04",
+	"Jan _2 15:04",
+	"Jan _2 2006",
+}
+
+// byteUnits maps size suffixes to their multiplier
+var byteUnits = map[string]float64{
+	"":  1,
+	"b": 1,
+	"k": 1 << 10, "kb": 1 << 10, "kib": 1 << 10,
+	"m": 1 << 20, "mb": 1 << 20, "mib": 1 << 20,
+	"g": 1 << 30, "gb": 1 << 30, "gib": 1 << 30,
+	"t": 1 << 40, "tb": 1 << 40, "tib": 1 << 40,
+	"p": 1 << 50, "pb": 1 << 50, "pib": 1 << 50,
+}
+
+// isNumeric returns whether values of this type are numbers
+func (ct ColumnType) isNumeric() bool {
+	switch ct {
+	case ColumnInt, ColumnFloat, ColumnBytes, ColumnDuration:
+		return true
+	}
+	return false
+}
+
+// alignment returns the alignment used to draw the column. Numeric
+// columns are right-aligned unless an alignment other than the default
+// AlignLeft is set.
+func (c Column) alignment() Alignment {
+	if c.Alignment == AlignLeft && c.Type.isNumeric() {
+		return AlignRight
+	}
+	return c.Alignment
+}
+
+// sortValue is a cell value along with the number it parses to, so that
+// sorts parse each cell once rather than on every comparison
+type sortValue struct {
+	text   string
+	number float64
+	parsed bool
+}
+
+// sortValue parses a cell value of this column for comparing. Values are
+// left unparsed when the column is compared as text or by Less.
+func (c Column) sortValue(text string) sortValue {
+	v := sortValue{text: text}
+	if c.Less == nil && c.Type != ColumnString {
+		v.number, v.parsed = c.parse(text)
+	}
+	return v
+}
+
+// compare orders two cell values of this column. It returns a negative
+// number if a sorts before b, a positive number if after, and 0 if equal.
+// Values that can't be parsed as the column type sort after those that
+// can, and are compared as text among themselves. Sorts use sortCompare,
+// which keeps them last in descending order too.
+func (c Column) compare(a, b string) int {
+	return c.compareValues(c.sortValue(a), c.sortValue(b))
+}
+
+// compareValues is compare for values already parsed by sortValue
+func (c Column) compareValues(a, b sortValue) int {
+	if c.Less != nil {
+		switch {
+		case c.Less(a.text, b.text):
+			return -1
+		case c.Less(b.text, a.text):
+			return 1
+		}
+		return 0
+	}
+
+	switch {
+	case a.parsed && b.parsed:
+		if a.number < b.number {
+			return -1
+		}
+		if a.number > b.number {
+			return 1
+		}
+		return 0
+	case a.parsed:
+		return -1
+	case b.parsed:
+		return 1
+	}
+	return strings.Compare(a.text, b.text)
+}
+
+// sortCompare orders two values parsed by sortValue for a sort in the
+// given direction. It returns a negative number if a sorts first. Values
+// that can't be parsed as the column type sort last in both directions.
+func (c Column) sortCompare(a, b sortValue, ascending bool) int {
+	if c.Less == nil && c.Type != ColumnString {
+		switch {
+		case a.parsed && !b.parsed:
+			return -1
+		case b.parsed && !a.parsed:
+			return 1
+		}
+	}
+	if ascending {
+		return c.compareValues(a, b)
+	}
+	return -c.compareValues(a, b)
+}
+
+// parse converts a cell value into a number that orders like the value
+func (c Column) parse(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	switch c.Type {
+	case ColumnInt:
+		n, err := strconv.ParseInt(strings.ReplaceAll(value, ",", ""), 10, 64)
+		return float64(n), err == nil
+	case ColumnFloat:
+		return parseFloat(value)
+	case ColumnBytes:
+		return parseBytes(value)
+	case ColumnDuration:
+		d, ok := parseDuration(value)
+		return float64(d), ok
+	case ColumnTime:
+		layouts := defaultTimeLayouts
+		if c.TimeLayout != "" {
+			layouts = []string{c.TimeLayout}
+		}
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return float64(t.UnixNano()), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// parseFloat parses a decimal number, ignoring thousands separators and a
+// trailing percent sign
+func parseFloat(value string) (float64, bool) {
+	value = strings.TrimSuffix(strings.ReplaceAll(value, ",", ""), "%")
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || math.IsNaN(f) {
+		return 0, false
+	}
+	return f, true
+}
+
+// parseBytes parses a size such as "512", "200M", "1.5G" or "3 KiB".
+// Both decimal and binary suffixes use powers of 1024.
+func parseBytes(value string) (float64, bool) {
+	end := len(value)
+	for end > 0 && !isDigit(value[end-1]) {
+		end--
+	}
+	number, ok := parseFloat(value[:end])
+	if !ok {
+		return 0, false
+	}
+	unit, ok := byteUnits[strings.ToLower(strings.TrimSpace(value[end:]))]
+	if !ok {
+		return 0, false
+	}
+	return number * unit, true
+}
+
+// parseDuration parses Go durations ("1h30m") as well as clock-style
+// durations as printed by ps ("05:12", "01:05:12", "2-01:05:12")
+func parseDuration(value string) (time.Duration, bool) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return d, true
+	}
+
+	var days time.Duration
+	if i := strings.IndexAny(value, "-d"); i > 0 {
+		n, err := strconv.Atoi(value[:i])
+		if err != nil {
+			return 0, false
+		}
+		days = time.Duration(n) * 24 * time.Hour
+		value = strings.TrimSpace(value[i+1:])
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+	var d time.Duration
+	for _, part := range parts {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil || n < 0 {
+			return 0, false
+		}
+		d = d*60 + time.Duration(n*float64(time.Second))
+	}
+	return days + d, true
+}
+
+// isDigit reports whether b is an ASCII digit
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
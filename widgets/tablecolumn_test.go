@@ -0,0 +1,98 @@
+package widgets
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestColumnCompare(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		col  Column
+		a, b string
+		want int
+	}{
+		{"string", Column{Type: ColumnString}, "10", "9", -1},
+		{"int", Column{Type: ColumnInt}, "10", "9", 1},
+		{"int separators", Column{Type: ColumnInt}, "1,024", "999", 1},
+		{"float", Column{Type: ColumnFloat}, "2.5", "12.5", -1},
+		{"float percent", Column{Type: ColumnFloat}, "9.5%", "10%", -1},
+		{"bytes", Column{Type: ColumnBytes}, "1.5G", "200M", 1},
+		{"bytes spaced", Column{Type: ColumnBytes}, "3 KiB", "3072", 0},
+		{"duration go", Column{Type: ColumnDuration}, "1h30m", "45m", 1},
+		{"duration clock", Column{Type: ColumnDuration}, "05:12", "1:00:00", -1},
+		{"duration days", Column{Type: ColumnDuration}, "2-01:00:00", "23:59:59", 1},
+		{"time", Column{Type: ColumnTime}, "2024-01-02", "2023-12-31", 1},
+		{"time layout", Column{Type: ColumnTime, TimeLayout: "02/01/2006"}, "01/02/2024", "31/01/2024", 1},
+		{"unparsable last", Column{Type: ColumnInt}, "n/a", "5", 1},
+		{"both unparsable", Column{Type: ColumnInt}, "a", "b", -1},
+		{"custom less", Column{Less: func(a, b string) bool { return len(a) < len(b) }}, "ccc", "a", 1},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.col.compare(tc.a, tc.b); sign(got) != tc.want {
+				t.Errorf("compare(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestColumnAlignment(t *testing.T) {
+	t.Parallel()
+
+	if (Column{Type: ColumnInt}).alignment() != AlignRight {
+		t.Error("Numeric columns should be right-aligned by default")
+	}
+	if (Column{Type: ColumnBytes, Alignment: AlignCenter}).alignment() != AlignCenter {
+		t.Error("An explicit alignment should be kept")
+	}
+	if (Column{Type: ColumnTime}).alignment() != AlignLeft {
+		t.Error("Time columns should keep the default alignment")
+	}
+}
+
+func TestTableTypedSort(t *testing.T) {
+	t.Parallel()
+	screen := createTestScreen(t)
+	defer screen.Fini()
+
+	table := NewTable(screen, 0, 0, 80, 24)
+	table.SetColumns([]Column{
+		{Title: "PID", Type: ColumnInt},
+		{Title: "MEM", Type: ColumnBytes},
+	})
+	table.SetRows([][]string{
+		{"9", "200M"},
+		{"n/a", "n/a"},
+		{"10", "1.5G"},
+		{"100", "512K"},
+	})
+
+	table.SetSortColumn(1)
+	got := []string{table.Rows[0][1], table.Rows[1][1], table.Rows[2][1], table.Rows[3][1]}
+	if strings.Join(got, ",") != "512K,200M,1.5G,n/a" {
+		t.Errorf("Expected rows ordered by size, got %v", got)
+	}
+
+	table.SetSortColumn(0)
+	table.SetSortColumn(0)
+	got = []string{table.Rows[0][0], table.Rows[1][0], table.Rows[2][0], table.Rows[3][0]}
+	if strings.Join(got, ",") != "100,10,9,n/a" {
+		t.Errorf("Expected rows ordered by PID descending with n/a last, got %v", got)
+	}
+}
+
+// sign returns -1, 0 or 1 according to the sign of n
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
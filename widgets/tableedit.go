@@ -0,0 +1,268 @@
+package widgets
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// cellEditor is the line editor open over a cell. It remembers the row by
+// source index, and by row key if the table has one, so it stays on the
+// same row when the view changes.
+type cellEditor struct {
+	active bool
+	src    int    // source index of the row
+	key    string // row key of the row, if keyed
+	keyed  bool
+	col    int
+	text   []rune
+	cursor int
+	err    error
+}
+
+// EditCell opens the line editor over a cell of an editable column. It
+// returns false if the row or column can't be edited.
+func (t *Table) EditCell(row, col int) bool {
+	if row < 0 || row >= t.rowCount() || col < 0 || col >= len(t.Columns) {
+		return false
+	}
+	if !t.Columns[col].Editable || t.Columns[col].Hidden {
+		return false
+	}
+	cells := t.displayRow(row)
+	text := []rune(cellValue(cells, col))
+	t.edit = cellEditor{active: true, src: t.rowIndex(row), col: col, text: text, cursor: len(text)}
+	if t.RowKey != nil {
+		t.edit.key, t.edit.keyed = t.RowKey(cells), true
+	}
+	t.SelectedRow = row
+	t.CurrentColumn = col
+	t.scrollToSelected()
+	t.scrollToColumn(t.columnPosition(col))
+	return true
+}
+
+// Editing returns whether a cell is being edited
+func (t *Table) Editing() bool {
+	return t.edit.active
+}
+
+// EditError returns the error OnEdit returned for the value being edited,
+// or nil
+func (t *Table) EditError() error {
+	return t.edit.err
+}
+
+// CancelEdit closes the editor without changing the cell
+func (t *Table) CancelEdit() {
+	t.edit = cellEditor{}
+}
+
+// CommitEdit passes the edited value to OnEdit and, unless it returns an
+// error, stores it in the cell and closes the editor. Rows aren't sorted
+// or filtered again until the next Refresh, so the row stays in place.
+// Tables backed by a Model leave storing the value to OnEdit. If the row
+// is gone, the edit is cancelled and false returned.
+func (t *Table) CommitEdit() bool {
+	if !t.edit.active {
+		return false
+	}
+	t.flush()
+	src, col, value := t.edit.src, t.edit.col, string(t.edit.text)
+	if !t.editRowValid() {
+		t.CancelEdit()
+		return false
+	}
+	if t.OnEdit != nil {
+		if err := t.OnEdit(t.sourceRow(src), col, value); err != nil {
+			t.edit.err = err
+			return false
+		}
+	}
+
+	if t.Model == nil {
+		for len(t.Rows[src]) <= col {
+			t.Rows[src] = append(t.Rows[src], "")
+		}
+		t.Rows[src][col] = value
+		if t.TreeMode() {
+			t.treeNodes[src].Cells = t.Rows[src]
+		}
+		t.measureRow(t.Rows[src])
+		t.refreshFooter()
+		t.adjustColumnWidths()
+	}
+	t.CancelEdit()
+	return true
+}
+
+// editRowValid returns whether the edited row is still there
+func (t *Table) editRowValid() bool {
+	src := t.edit.src
+	if src < 0 || src >= t.sourceCount() {
+		return false
+	}
+	return !t.edit.keyed || t.RowKey(t.sourceRow(src)) == t.edit.key
+}
+
+// editRow returns the display index of the edited row, or -1 if it is
+// filtered out or hidden in a collapsed node
+func (t *Table) editRow() int {
+	if !t.edit.active {
+		return -1
+	}
+	if t.view == nil {
+		return t.edit.src
+	}
+	for i, src := range t.view {
+		if src == t.edit.src {
+			return i
+		}
+	}
+	return -1
+}
+
+// followEdit finds the edited row again after the rows were replaced or
+// reordered: by its row key if the table has one, and otherwise by
+// closing the editor, since the row can't be told apart from others
+func (t *Table) followEdit() {
+	if !t.edit.active {
+		return
+	}
+	if t.edit.keyed {
+		if t.editRowValid() {
+			return
+		}
+		for i := 0; i < t.sourceCount(); i++ {
+			if t.RowKey(t.sourceRow(i)) == t.edit.key {
+				t.edit.src = i
+				return
+			}
+		}
+	}
+	t.CancelEdit()
+}
+
+// nextEditableCell returns the editable cell after (or before, if
+// direction is negative) the given one in display order, moving on to the
+// next row at the end of a row
+func (t *Table) nextEditableCell(row, col, direction int) (int, int, bool) {
+	order := t.displayOrder()
+	pos := t.columnPosition(col)
+	step := 1
+	if direction < 0 {
+		step = -1
+	}
+	for {
+		pos += step
+		if pos < 0 || pos >= len(order) {
+			row += step
+			if row < 0 || row >= t.rowCount() {
+				return 0, 0, false
+			}
+			pos = 0
+			if step < 0 {
+				pos = len(order) - 1
+			}
+		}
+		c := t.Columns[order[pos]]
+		if c.Editable && !c.Hidden {
+			return row, order[pos], true
+		}
+	}
+}
+
+// handleEditKey opens the editor on Enter or F2, in the current column if
+// it is editable and in the first editable one otherwise
+func (t *Table) handleEditKey(ev *tcell.EventKey) bool {
+	if ev.Key() != tcell.KeyEnter && ev.Key() != tcell.KeyF2 {
+		return false
+	}
+	if t.CurrentColumn >= 0 && t.CurrentColumn < len(t.Columns) && t.EditCell(t.SelectedRow, t.CurrentColumn) {
+		return true
+	}
+	for _, col := range t.displayOrder() {
+		if t.EditCell(t.SelectedRow, col) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleEditorKey handles keys while the editor is open. Enter commits,
+// Esc cancels, and Tab and Shift+Tab commit and move to the next or
+// previous editable cell.
+func (t *Table) handleEditorKey(ev *tcell.EventKey) bool {
+	e := &t.edit
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		t.CancelEdit()
+	case tcell.KeyEnter:
+		t.CommitEdit()
+	case tcell.KeyTab, tcell.KeyBacktab:
+		direction := 1
+		if ev.Key() == tcell.KeyBacktab {
+			direction = -1
+		}
+		row, col := t.editRow(), e.col
+		if t.CommitEdit() && row >= 0 {
+			if row, col, ok := t.nextEditableCell(row, col, direction); ok {
+				t.EditCell(row, col)
+			}
+		}
+	case tcell.KeyLeft:
+		if e.cursor > 0 {
+			e.cursor--
+		}
+	case tcell.KeyRight:
+		if e.cursor < len(e.text) {
+			e.cursor++
+		}
+	case tcell.KeyHome, tcell.KeyCtrlA:
+		e.cursor = 0
+	case tcell.KeyEnd, tcell.KeyCtrlE:
+		e.cursor = len(e.text)
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if e.cursor > 0 {
+			e.text = append(e.text[:e.cursor-1], e.text[e.cursor:]...)
+			e.cursor--
+			e.err = nil
+		}
+	case tcell.KeyDelete:
+		if e.cursor < len(e.text) {
+			e.text = append(e.text[:e.cursor], e.text[e.cursor+1:]...)
+			e.err = nil
+		}
+	case tcell.KeyCtrlU:
+		e.text = e.text[:0]
+		e.cursor = 0
+		e.err = nil
+	case tcell.KeyRune:
+		e.text = append(e.text[:e.cursor], append([]rune{ev.Rune()}, e.text[e.cursor:]...)...)
+		e.cursor++
+		e.err = nil
+	}
+	return true
+}
+
+// drawEditor draws the editor over its cell, scrolled so the cursor stays
+// visible
+func (t *Table) drawEditor(span columnSpan, y int) {
+	style := t.EditStyle
+	if t.edit.err != nil {
+		style = t.PromptErrorStyle
+	}
+	start := 0
+	if t.edit.cursor >= span.width {
+		start = t.edit.cursor - span.width + 1
+	}
+	for i := 0; i < span.width; i++ {
+		r := ' '
+		if start+i < len(t.edit.text) {
+			r = t.edit.text[start+i]
+		}
+		cellStyle := style
+		if start+i == t.edit.cursor {
+			cellStyle = style.Reverse(true) // cursor
+		}
+		t.Screen.SetContent(span.x+i, y, r, nil, cellStyle)
+	}
+}
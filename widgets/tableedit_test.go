@@ -0,0 +1,157 @@
+package widgets
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestTableEdit(t *testing.T) {
+	t.Parallel()
+	table, screen := newProcessTable(t)
+	table.Columns[1].Editable = true
+	table.Columns[2].Editable = true
+
+	var edits []string
+	table.OnEdit = func(row []string, col int, value string) error {
+		if value == "" {
+			return errors.New("empty value")
+		}
+		edits = append(edits, row[0]+":"+value)
+		return nil
+	}
+
+	key := func(k tcell.Key) {
+		table.HandleEvent(tcell.NewEventKey(k, 0, tcell.ModNone))
+	}
+
+	// Enter opens the first editable column of the selected row
+	key(tcell.KeyEnter)
+	if !table.Editing() || table.edit.col != 1 {
+		t.Fatalf("Expected the User column edited, got %+v", table.edit)
+	}
+	key(tcell.KeyCtrlU)
+	typeKeys(table, "admin")
+
+	table.Draw()
+	if got := readRow(screen, 5, 2, 6); got != "admin " {
+		t.Errorf("Expected the editor drawn over the cell, got %q", got)
+	}
+
+	// Tab commits and moves to the next editable cell
+	key(tcell.KeyTab)
+	if table.Rows[0][1] != "admin" || table.edit.col != 2 || table.editRow() != 0 {
+		t.Errorf("Expected the value stored and Command edited, got %q %+v", table.Rows[0], table.edit)
+	}
+	key(tcell.KeyTab)
+	if table.editRow() != 1 || table.edit.col != 1 {
+		t.Errorf("Expected Tab to wrap to the next row, got %+v", table.edit)
+	}
+
+	// Esc cancels without calling OnEdit
+	typeKeys(table, "xyz")
+	key(tcell.KeyEscape)
+	if table.Editing() || table.Rows[1][1] != "www" {
+		t.Errorf("Expected the edit cancelled, got %q", table.Rows[1])
+	}
+
+	// A rejected value keeps the editor open
+	table.CurrentColumn = 2
+	key(tcell.KeyF2)
+	key(tcell.KeyCtrlU)
+	key(tcell.KeyEnter)
+	if !table.Editing() || table.EditError() == nil {
+		t.Error("Expected the empty value rejected")
+	}
+	typeKeys(table, "httpd")
+	if table.EditError() != nil {
+		t.Error("Expected typing to clear the error")
+	}
+	key(tcell.KeyEnter)
+	if table.Editing() || table.Rows[1][2] != "httpd" {
+		t.Errorf("Expected the value committed, got %q", table.Rows[1])
+	}
+
+	if want := "[1:admin 1:init 200:httpd]"; fmt.Sprint(edits) != want {
+		t.Errorf("Unexpected OnEdit calls: %v", edits)
+	}
+
+	// Columns that aren't editable can't be edited
+	if table.EditCell(0, 0) {
+		t.Error("Expected PID not to be editable")
+	}
+}
+
+func TestTableEditCursor(t *testing.T) {
+	t.Parallel()
+	table, _ := newProcessTable(t)
+	table.Columns[2].Editable = true
+	table.EditCell(0, 2)
+
+	for _, k := range []tcell.Key{tcell.KeyHome, tcell.KeyDelete, tcell.KeyRight} {
+		table.HandleEvent(tcell.NewEventKey(k, 0, tcell.ModNone))
+	}
+	typeKeys(table, "X")
+	table.HandleEvent(tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModNone))
+	table.HandleEvent(tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone))
+	table.CommitEdit()
+	if got := table.Rows[0][2]; got != "nXi" {
+		t.Errorf("Expected \"nXi\", got %q", got)
+	}
+}
+
+func TestTableEditFollowsRow(t *testing.T) {
+	t.Parallel()
+	table, _ := newProcessTable(t)
+	table.Columns[2].Editable = true
+	table.SetSortColumn(0)
+
+	// A row added and sorted in front of the edited one doesn't redirect
+	// the edit
+	table.EditCell(1, 2) // nginx
+	table.AddRow([]string{"100", "root", "cron"})
+	table.Draw()
+	if row := table.editRow(); row != 2 {
+		t.Errorf("Expected the editor to move with its row, got row %d", row)
+	}
+	table.edit.text = []rune("httpd")
+	if !table.CommitEdit() || table.Rows[2][2] != "httpd" || table.Rows[1][2] != "cron" {
+		t.Errorf("Expected nginx renamed, got %v", table.Rows)
+	}
+
+	// Filtering the row out keeps it the target
+	table.EditCell(3, 2) // nginx-worker
+	if err := table.SetFilter("vim"); err != nil {
+		t.Fatal(err)
+	}
+	if table.editRow() != -1 {
+		t.Error("Expected the edited row filtered out")
+	}
+	table.edit.text = []rune("worker")
+	if !table.CommitEdit() || table.Rows[3][2] != "worker" || table.Rows[4][2] != "vim" {
+		t.Errorf("Expected the filtered out row edited, got %v", table.Rows)
+	}
+	table.ClearFilter()
+
+	// Without a row key, replacing the rows closes the editor
+	table.EditCell(0, 2)
+	table.SetRows([][]string{{"5", "root", "init"}})
+	if table.Editing() {
+		t.Error("Expected replacing the rows to cancel the edit")
+	}
+
+	// With one, the editor follows the row and closes once it is gone
+	table.SetRowKey(KeyColumn(0))
+	table.SetRows([][]string{{"5", "root", "init"}, {"7", "root", "top"}})
+	table.EditCell(1, 2)
+	table.SetRows([][]string{{"7", "root", "top"}, {"6", "root", "ps"}})
+	if !table.Editing() || table.editRow() != 1 {
+		t.Errorf("Expected the editor to follow the row by key, got row %d", table.editRow())
+	}
+	table.SetRows([][]string{{"6", "root", "ps"}})
+	if table.Editing() || table.CommitEdit() {
+		t.Error("Expected the edit cancelled once its row is gone")
+	}
+}
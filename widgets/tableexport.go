@@ -0,0 +1,174 @@
+package widgets
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ExportFormat is a file format a table can be exported to
+type ExportFormat int
+
+// Export formats
+const (
+	// ExportCSV writes comma-separated values with a header line
+	ExportCSV ExportFormat = iota
+	// ExportTSV writes tab-separated values with a header line
+	ExportTSV
+	// ExportJSON writes an array with one object per row, keyed by title
+	ExportJSON
+	// ExportMarkdown writes a GitHub Flavored Markdown table
+	ExportMarkdown
+)
+
+// ParseExportFormat returns the format with the given name: csv, tsv,
+// json, markdown or md
+func ParseExportFormat(name string) (ExportFormat, error) {
+	switch strings.ToLower(name) {
+	case "csv":
+		return ExportCSV, nil
+	case "tsv":
+		return ExportTSV, nil
+	case "json":
+		return ExportJSON, nil
+	case "markdown", "md":
+		return ExportMarkdown, nil
+	}
+	return 0, fmt.Errorf("unknown export format %q", name)
+}
+
+// Export writes the rows as currently shown, sorted and filtered, to w.
+// Columns are written in display order; hidden columns are included only
+// if allColumns is true.
+func (t *Table) Export(w io.Writer, format ExportFormat, allColumns bool) error {
+	var columns []int
+	for _, i := range t.displayOrder() {
+		if allColumns || !t.Columns[i].Hidden {
+			columns = append(columns, i)
+		}
+	}
+
+	switch format {
+	case ExportCSV:
+		return t.exportDelimited(w, ',', columns)
+	case ExportTSV:
+		return t.exportDelimited(w, '\t', columns)
+	case ExportJSON:
+		return t.exportJSON(w, columns)
+	case ExportMarkdown:
+		return t.exportMarkdown(w, columns)
+	}
+	return fmt.Errorf("unknown export format %d", format)
+}
+
+// exportRecord returns the values of the given columns in a row
+func exportRecord(row []string, columns []int) []string {
+	record := make([]string, len(columns))
+	for i, col := range columns {
+		record[i] = cellValue(row, col)
+	}
+	return record
+}
+
+// exportDelimited writes the rows as delimiter-separated values
+func (t *Table) exportDelimited(w io.Writer, delimiter rune, columns []int) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = delimiter
+
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = t.Columns[col].Title
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for i := 0; i < t.rowCount(); i++ {
+		if err := cw.Write(exportRecord(t.displayRow(i), columns)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// exportJSON writes the rows as an array of objects. Keys keep the column
+// order, which encoding a map would lose.
+func (t *Table) exportJSON(w io.Writer, columns []int) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("[")
+	for i := 0; i < t.rowCount(); i++ {
+		if i > 0 {
+			bw.WriteString(",")
+		}
+		bw.WriteString("\n  {")
+		for j, value := range exportRecord(t.displayRow(i), columns) {
+			if j > 0 {
+				bw.WriteString(", ")
+			}
+			key, err := json.Marshal(t.Columns[columns[j]].Title)
+			if err != nil {
+				return err
+			}
+			data, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			bw.Write(key)
+			bw.WriteString(": ")
+			bw.Write(data)
+		}
+		bw.WriteString("}")
+	}
+	if t.rowCount() > 0 {
+		bw.WriteString("\n")
+	}
+	bw.WriteString("]\n")
+	return bw.Flush()
+}
+
+// exportMarkdown writes the rows as a Markdown table aligned like the
+// columns
+func (t *Table) exportMarkdown(w io.Writer, columns []int) error {
+	bw := bufio.NewWriter(w)
+	writeLine := func(cells []string) {
+		bw.WriteString("|")
+		for _, cell := range cells {
+			bw.WriteString(" " + markdownEscape(cell) + " |")
+		}
+		bw.WriteString("\n")
+	}
+
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = t.Columns[col].Title
+	}
+	writeLine(header)
+
+	bw.WriteString("|")
+	for _, col := range columns {
+		switch t.Columns[col].alignment() {
+		case AlignRight:
+			bw.WriteString(" ---: |")
+		case AlignCenter:
+			bw.WriteString(" :---: |")
+		default:
+			bw.WriteString(" --- |")
+		}
+	}
+	bw.WriteString("\n")
+
+	for i := 0; i < t.rowCount(); i++ {
+		writeLine(exportRecord(t.displayRow(i), columns))
+	}
+	return bw.Flush()
+}
+
+// markdownEscape escapes text for use in a Markdown table cell
+func markdownEscape(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
@@ -0,0 +1,78 @@
+package widgets
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestTableExport(t *testing.T) {
+	t.Parallel()
+	table, _ := newProcessTable(t)
+	table.SetSortKeys(SortKey{Column: 0, Ascending: false})
+	if err := table.SetFilter("root"); err != nil {
+		t.Fatal(err)
+	}
+	table.SetColumnHidden(1, true)
+
+	tests := []struct {
+		format     ExportFormat
+		allColumns bool
+		want       string
+	}{
+		{ExportCSV, false, "PID,Command\n301,sshd\n1,init\n"},
+		{ExportTSV, true, "PID\tUser\tCommand\n301\troot\tsshd\n1\troot\tinit\n"},
+		{ExportMarkdown, false, "| PID | Command |\n| ---: | --- |\n| 301 | sshd |\n| 1 | init |\n"},
+		{ExportJSON, false, "[\n  {\"PID\": \"301\", \"Command\": \"sshd\"},\n  {\"PID\": \"1\", \"Command\": \"init\"}\n]\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := table.Export(&buf, tt.format, tt.allColumns); err != nil {
+			t.Fatalf("format %d: %v", tt.format, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("format %d: expected\n%s\ngot\n%s", tt.format, tt.want, buf.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := table.Export(&buf, ExportJSON, true); err != nil {
+		t.Fatal(err)
+	}
+	var rows []map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+		t.Fatalf("Expected valid JSON: %v", err)
+	}
+	if len(rows) != 2 || rows[0]["User"] != "root" {
+		t.Errorf("Expected hidden columns with allColumns, got %v", rows)
+	}
+}
+
+func TestTableExportEscaping(t *testing.T) {
+	t.Parallel()
+	table, _ := newProcessTable(t)
+	table.SetRows([][]string{{"1", "a|b", "say \"hi\", bye"}})
+
+	var buf bytes.Buffer
+	if err := table.Export(&buf, ExportCSV, false); err != nil {
+		t.Fatal(err)
+	}
+	if want := "PID,User,Command\n1,a|b,\"say \"\"hi\"\", bye\"\n"; buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+
+	buf.Reset()
+	if err := table.Export(&buf, ExportMarkdown, false); err != nil {
+		t.Fatal(err)
+	}
+	if want := "| 1 | a\\|b | say \"hi\", bye |\n"; !bytes.HasSuffix(buf.Bytes(), []byte(want)) {
+		t.Errorf("Expected escaped pipes, got %q", buf.String())
+	}
+
+	if _, err := ParseExportFormat("md"); err != nil {
+		t.Error(err)
+	}
+	if _, err := ParseExportFormat("xls"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
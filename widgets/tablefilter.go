@@ -0,0 +1,353 @@
+package widgets
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Table prompt modes
+const (
+	promptNone = iota
+	promptFilter
+	promptSearch
+)
+
+// matchTerm matches cell values against a substring or regular expression
+type matchTerm struct {
+	column int // -1 matches any column
+	substr string
+	re     *regexp.Regexp
+}
+
+// rowMatcher matches rows against a filter or search query. A row matches
+// when every term matches.
+type rowMatcher struct {
+	terms []matchTerm
+}
+
+// compileQuery parses a query. Terms are separated by spaces and may be
+// scoped to a column by title, e.g. "user:root". A term wrapped in slashes
+// is a regular expression, e.g. "/^ngi/". Matching is case-insensitive.
+func (t *Table) compileQuery(query string) (*rowMatcher, error) {
+	matcher := &rowMatcher{}
+	for _, field := range strings.Fields(query) {
+		term := matchTerm{column: -1}
+		if i := strings.Index(field, ":"); i > 0 {
+			if col := t.columnByTitle(field[:i]); col >= 0 {
+				term.column = col
+				field = field[i+1:]
+			}
+		}
+		if len(field) >= 2 && strings.HasPrefix(field, "/") && strings.HasSuffix(field, "/") {
+			re, err := regexp.Compile("(?i)" + field[1:len(field)-1])
+			if err != nil {
+				return nil, err
+			}
+			term.re = re
+		} else {
+			term.substr = strings.ToLower(field)
+		}
+		if term.re == nil && term.substr == "" {
+			continue
+		}
+		matcher.terms = append(matcher.terms, term)
+	}
+	if len(matcher.terms) == 0 {
+		return nil, nil
+	}
+	return matcher, nil
+}
+
+// columnByTitle returns the index of the column with the given title,
+// ignoring case, or -1
+func (t *Table) columnByTitle(title string) int {
+	for i, col := range t.Columns {
+		if strings.EqualFold(col.Title, title) {
+			return i
+		}
+	}
+	return -1
+}
+
+// find returns the rune ranges of value matched by the term
+func (term matchTerm) find(value string) [][2]int {
+	var byteRanges [][]int
+	if term.re != nil {
+		byteRanges = term.re.FindAllStringIndex(value, -1)
+	} else {
+		lower := strings.ToLower(value)
+		for start := 0; start < len(lower); {
+			i := strings.Index(lower[start:], term.substr)
+			if i < 0 {
+				break
+			}
+			byteRanges = append(byteRanges, []int{start + i, start + i + len(term.substr)})
+			start += i + len(term.substr)
+		}
+	}
+
+	ranges := make([][2]int, 0, len(byteRanges))
+	for _, r := range byteRanges {
+		if r[1] > r[0] {
+			ranges = append(ranges, [2]int{textWidth(value[:r[0]]), textWidth(value[:r[1]])})
+		}
+	}
+	return ranges
+}
+
+// matchRow returns whether every term matches the row
+func (m *rowMatcher) matchRow(row []string) bool {
+	for _, term := range m.terms {
+		matched := false
+		for col, value := range row {
+			if term.column >= 0 && term.column != col {
+				continue
+			}
+			if len(term.find(value)) > 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// ranges returns the rune ranges to highlight in a cell
+func (m *rowMatcher) ranges(col int, value string) [][2]int {
+	var ranges [][2]int
+	for _, term := range m.terms {
+		if term.column < 0 || term.column == col {
+			ranges = append(ranges, term.find(value)...)
+		}
+	}
+	return ranges
+}
+
+// SetFilter narrows the visible rows to those matching the query. An empty
+// query shows all rows again. Invalid regular expressions leave the current
+// filter in place and return an error.
+func (t *Table) SetFilter(query string) error {
+	matcher, err := t.compileQuery(query)
+	if err != nil {
+		return err
+	}
+	t.flush()
+	anchor := t.currentAnchor()
+	t.Filter = query
+	t.filter = matcher
+	t.refreshView()
+	t.SelectedRow = 0
+	t.ScrollOffset = 0
+	t.followAnchor(anchor)
+	t.captureAnchor()
+	return nil
+}
+
+// ClearFilter shows all rows again
+func (t *Table) ClearFilter() {
+	_ = t.SetFilter("")
+}
+
+// Filtered returns whether a filter is narrowing the visible rows
+func (t *Table) Filtered() bool {
+	return t.filter != nil
+}
+
+// SetSearch highlights rows matching the query without hiding the others
+// and selects the first match at or after the selected row. It returns
+// false if nothing matches.
+func (t *Table) SetSearch(query string) (bool, error) {
+	matcher, err := t.compileQuery(query)
+	if err != nil {
+		return false, err
+	}
+	t.Search = query
+	t.search = matcher
+	if matcher == nil {
+		return false, nil
+	}
+	return t.jumpToMatch(0), nil
+}
+
+// NextMatch selects the next row matching the search query
+func (t *Table) NextMatch() bool {
+	return t.jumpToMatch(1)
+}
+
+// PreviousMatch selects the previous row matching the search query
+func (t *Table) PreviousMatch() bool {
+	return t.jumpToMatch(-1)
+}
+
+// jumpToMatch selects the nearest matching row in the given direction,
+// wrapping around. A direction of 0 includes the selected row itself.
+func (t *Table) jumpToMatch(direction int) bool {
+	count := t.rowCount()
+	if t.search == nil || count == 0 {
+		return false
+	}
+	step := direction
+	if step == 0 {
+		step = 1
+	}
+	for i := 0; i < count; i++ {
+		offset := i*step + direction
+		if direction == 0 {
+			offset = i
+		}
+		idx := ((t.SelectedRow+offset)%count + count) % count
+		if t.search.matchRow(t.displayRow(idx)) {
+			t.SelectedRow = idx
+			t.scrollToSelected()
+			return true
+		}
+	}
+	return false
+}
+
+// highlighter returns the matcher whose matches are highlighted
+func (t *Table) highlighter() *rowMatcher {
+	if t.promptMode == promptFilter || (t.filter != nil && t.promptMode != promptSearch) {
+		return t.filter
+	}
+	return t.search
+}
+
+// refreshView rebuilds the list of visible rows
+func (t *Table) refreshView() {
+	if t.TreeMode() {
+		t.refreshTreeView()
+	} else if t.filter == nil {
+		t.view = nil
+	} else {
+		count := t.sourceCount()
+		t.view = make([]int, 0, count)
+		for i := 0; i < count; i++ {
+			if t.filter.matchRow(t.sourceRow(i)) {
+				t.view = append(t.view, i)
+			}
+		}
+	}
+	t.clampSelection()
+	t.refreshFooter()
+}
+
+// clampSelection keeps the selection and scroll offset inside the view
+func (t *Table) clampSelection() {
+	count := t.rowCount()
+	if t.SelectedRow >= count {
+		t.SelectedRow = count - 1
+	}
+	if t.SelectedRow < 0 {
+		t.SelectedRow = 0
+	}
+	if maxScroll := count - t.visibleRowCount(); t.ScrollOffset > maxScroll {
+		t.ScrollOffset = maxScroll
+	}
+	if t.ScrollOffset < 0 {
+		t.ScrollOffset = 0
+	}
+}
+
+// rowCount returns the number of visible rows
+func (t *Table) rowCount() int {
+	t.flush()
+	if t.view != nil {
+		return len(t.view)
+	}
+	return t.sourceCount()
+}
+
+// rowIndex returns the source index of a visible row
+func (t *Table) rowIndex(i int) int {
+	if t.view != nil {
+		return t.view[i]
+	}
+	return i
+}
+
+// displayRow returns the visible row at index i
+func (t *Table) displayRow(i int) []string {
+	return t.sourceRow(t.rowIndex(i))
+}
+
+// openPrompt starts editing a filter or search query
+func (t *Table) openPrompt(mode int) {
+	t.promptMode = mode
+	t.promptErr = nil
+	if mode == promptFilter {
+		t.promptText = t.Filter
+	} else {
+		t.promptText = ""
+	}
+}
+
+// handlePromptKey handles keys while the prompt is open. Filters are
+// applied as the query is typed, and searches jump to the first match.
+func (t *Table) handlePromptKey(ev *tcell.EventKey) bool {
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		if t.promptMode == promptFilter {
+			t.ClearFilter()
+		} else {
+			_, _ = t.SetSearch("")
+		}
+		t.promptMode = promptNone
+		return true
+	case tcell.KeyEnter:
+		t.promptMode = promptNone
+		return true
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		runes := []rune(t.promptText)
+		if len(runes) == 0 {
+			return true
+		}
+		t.promptText = string(runes[:len(runes)-1])
+	case tcell.KeyRune:
+		t.promptText += string(ev.Rune())
+	default:
+		return true
+	}
+
+	if t.promptMode == promptFilter {
+		t.promptErr = t.SetFilter(t.promptText)
+	} else {
+		_, t.promptErr = t.SetSearch(t.promptText)
+	}
+	return true
+}
+
+// drawPrompt draws the query being edited on the last line of the table
+func (t *Table) drawPrompt(y int) {
+	left, width := t.innerBounds()
+	prefix := "/"
+	if t.promptMode == promptSearch {
+		prefix = "?"
+	}
+
+	style := t.Style
+	if t.promptErr != nil {
+		style = t.PromptErrorStyle
+	}
+	text := []rune(prefix + t.promptText)
+	status := []rune(fmt.Sprintf(" %d/%d", t.rowCount(), t.sourceCount()))
+	for i := 0; i < width; i++ {
+		r := ' '
+		cellStyle := style
+		switch {
+		case i < len(text):
+			r = text[i]
+		case i == len(text):
+			cellStyle = style.Reverse(true) // cursor
+		case i >= width-len(status):
+			r = status[i-(width-len(status))]
+		}
+		t.Screen.SetContent(left+i, y, r, nil, cellStyle)
+	}
+}
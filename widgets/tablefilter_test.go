@@ -0,0 +1,173 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func newProcessTable(t *testing.T) (*Table, tcell.SimulationScreen) {
+	t.Helper()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(40, 10)
+
+	table := NewTable(screen, 0, 0, 30, 8)
+	table.SortColumn = -1
+	table.SetColumns([]Column{
+		{Title: "PID", Type: ColumnInt},
+		{Title: "User"},
+		{Title: "Command"},
+	})
+	table.SetRows([][]string{
+		{"1", "root", "init"},
+		{"200", "www", "nginx"},
+		{"201", "www", "nginx-worker"},
+		{"300", "alice", "vim"},
+		{"301", "root", "sshd"},
+	})
+	return table, screen
+}
+
+func typeKeys(table *Table, text string) {
+	for _, r := range text {
+		table.HandleEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+}
+
+func TestTableFilter(t *testing.T) {
+	t.Parallel()
+	table, _ := newProcessTable(t)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"nginx", []string{"200", "201"}},
+		{"NGINX", []string{"200", "201"}},
+		{"user:root", []string{"1", "301"}},
+		{"user:root sshd", []string{"301"}},
+		{"/^n.*x$/", []string{"200"}},
+		{"command:/^(vim|init)$/", []string{"1", "300"}},
+		{"nosuch:root", nil},
+		{"", []string{"1", "200", "201", "300", "301"}},
+	}
+	for _, tt := range tests {
+		if err := table.SetFilter(tt.query); err != nil {
+			t.Fatalf("SetFilter(%q): %v", tt.query, err)
+		}
+		var got []string
+		for i := 0; i < table.rowCount(); i++ {
+			got = append(got, table.displayRow(i)[0])
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("SetFilter(%q): expected %v, got %v", tt.query, tt.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("SetFilter(%q): expected %v, got %v", tt.query, tt.want, got)
+				break
+			}
+		}
+	}
+
+	if err := table.SetFilter("/[/"); err == nil {
+		t.Error("Expected an error for an invalid regular expression")
+	}
+}
+
+func TestTableFilterPrompt(t *testing.T) {
+	t.Parallel()
+	table, screen := newProcessTable(t)
+
+	typeKeys(table, "/ngi")
+	if table.rowCount() != 2 {
+		t.Fatalf("Expected rows to narrow while typing, got %d", table.rowCount())
+	}
+	table.HandleEvent(tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone))
+	table.HandleEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	if table.Filter != "ng" {
+		t.Errorf("Expected filter %q, got %q", "ng", table.Filter)
+	}
+
+	table.HandleEvent(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone))
+	row, idx := table.GetSelectedRow()
+	if idx != 1 || row[0] != "201" {
+		t.Errorf("Expected the second filtered row to be selected, got %v at %d", row, idx)
+	}
+
+	table.Draw()
+	// The match in "nginx" is highlighted
+	_, _, style, _ := screen.GetContent(table.Columns[0].Width+table.Columns[1].Width+4, 2)
+	if style != table.MatchStyle {
+		t.Error("Expected the matched text to use MatchStyle")
+	}
+
+	table.HandleEvent(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	if table.Filtered() || table.rowCount() != 5 {
+		t.Errorf("Expected Esc to clear the filter, got %d rows", table.rowCount())
+	}
+}
+
+func TestTableSearch(t *testing.T) {
+	t.Parallel()
+	table, _ := newProcessTable(t)
+
+	typeKeys(table, "?root")
+	table.HandleEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	if table.SelectedRow != 0 {
+		t.Errorf("Expected first match at row 0, got %d", table.SelectedRow)
+	}
+	if table.rowCount() != 5 {
+		t.Errorf("Expected search to keep all rows, got %d", table.rowCount())
+	}
+
+	typeKeys(table, "n")
+	if table.SelectedRow != 4 {
+		t.Errorf("Expected n to jump to row 4, got %d", table.SelectedRow)
+	}
+	typeKeys(table, "n")
+	if table.SelectedRow != 0 {
+		t.Errorf("Expected n to wrap around to row 0, got %d", table.SelectedRow)
+	}
+	typeKeys(table, "N")
+	if table.SelectedRow != 4 {
+		t.Errorf("Expected N to jump back to row 4, got %d", table.SelectedRow)
+	}
+
+	if err := table.SetFilter("www"); err != nil {
+		t.Fatal(err)
+	}
+	if table.HandleEvent(tcell.NewEventKey(tcell.KeyRune, 'n', tcell.ModNone)) {
+		t.Error("Expected n to do nothing while filtered")
+	}
+}
+
+func TestTableMatchesInClippedColumn(t *testing.T) {
+	t.Parallel()
+	screen := createTestScreen(t)
+	screen.SetSize(20, 5)
+	table := NewTable(screen, 0, 0, 12, 5)
+	table.SetShowBorder(false)
+	table.MatchStyle = tcell.StyleDefault.Foreground(tcell.ColorRed)
+	table.SetColumns([]Column{{Title: "Name", Alignment: AlignRight, FixedWidth: 20}})
+	table.SetRows([][]string{{"nginx"}})
+	if err := table.SetFilter("ngi"); err != nil {
+		t.Fatal(err)
+	}
+	table.Draw()
+
+	// The column is clipped to 12 cells, so the value ends at the edge
+	if got := readRow(screen, 0, 1, 12); got != "       nginx" {
+		t.Fatalf("Expected the value right-aligned in the clipped column, got %q", got)
+	}
+	for x := 7; x < 12; x++ {
+		_, _, style, _ := screen.GetContent(x, 1)
+		if matched := style == table.MatchStyle; matched != (x < 10) {
+			t.Errorf("Column %d: expected matched=%v", x, x < 10)
+		}
+	}
+}
@@ -0,0 +1,390 @@
+package widgets
+
+import (
+	"encoding/json"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ColumnLayout is the saved state of one column
+type ColumnLayout struct {
+	Title  string `json:"title"`
+	Width  int    `json:"width,omitempty"`
+	Hidden bool   `json:"hidden,omitempty"`
+}
+
+// TableLayout is the user-adjustable column layout of a table: the order,
+// widths and visibility of its columns. It can be saved as JSON and
+// restored with SetLayout, so tools remember it between runs.
+type TableLayout struct {
+	Columns []ColumnLayout `json:"columns"`
+}
+
+// columnDrag tracks a header drag started with the mouse
+type columnDrag struct {
+	active     bool
+	resize     bool
+	col        int
+	startX     int
+	startWidth int
+	moved      bool
+	shift      bool
+}
+
+// displayOrder returns the indices of Columns in display order
+func (t *Table) displayOrder() []int {
+	if len(t.order) != len(t.Columns) {
+		t.order = make([]int, len(t.Columns))
+		for i := range t.order {
+			t.order[i] = i
+		}
+	}
+	return t.order
+}
+
+// columnPosition returns the display position of a column, or -1
+func (t *Table) columnPosition(col int) int {
+	for pos, i := range t.displayOrder() {
+		if i == col {
+			return pos
+		}
+	}
+	return -1
+}
+
+// MoveColumn moves a column delta display positions to the right, or to
+// the left if delta is negative. It returns false if the column can't move.
+func (t *Table) MoveColumn(col, delta int) bool {
+	from := t.columnPosition(col)
+	if from < 0 || delta == 0 {
+		return false
+	}
+	to := from + delta
+	if to < 0 {
+		to = 0
+	}
+	if to >= len(t.order) {
+		to = len(t.order) - 1
+	}
+	if to == from {
+		return false
+	}
+
+	if to > from {
+		copy(t.order[from:to], t.order[from+1:to+1])
+	} else {
+		copy(t.order[to+1:from+1], t.order[to:from])
+	}
+	t.order[to] = col
+	if t.TreeMode() {
+		t.adjustColumnWidths() // the guides may have moved to another column
+	}
+	return true
+}
+
+// SetColumnHidden hides or shows a column. The last visible column can't
+// be hidden.
+func (t *Table) SetColumnHidden(col int, hidden bool) {
+	if col < 0 || col >= len(t.Columns) || t.Columns[col].Hidden == hidden {
+		return
+	}
+	if hidden && t.visibleColumnCount() == 1 {
+		return
+	}
+	t.Columns[col].Hidden = hidden
+	if hidden && col == t.CurrentColumn {
+		if !t.selectColumn(1) {
+			t.selectColumn(-1)
+		}
+	}
+	t.adjustColumnWidths()
+}
+
+// ShowAllColumns shows every hidden column
+func (t *Table) ShowAllColumns() {
+	for i := range t.Columns {
+		t.Columns[i].Hidden = false
+	}
+	t.adjustColumnWidths()
+}
+
+// SetColumnWidth fixes the width of a column. A width of zero or less
+// returns the column to automatic sizing.
+func (t *Table) SetColumnWidth(col, width int) {
+	if col < 0 || col >= len(t.Columns) {
+		return
+	}
+	if width < 0 {
+		width = 0
+	}
+	t.Columns[col].FixedWidth = width
+	t.adjustColumnWidths()
+}
+
+// visibleColumnCount returns the number of columns that aren't hidden
+func (t *Table) visibleColumnCount() int {
+	count := 0
+	for _, col := range t.Columns {
+		if !col.Hidden {
+			count++
+		}
+	}
+	return count
+}
+
+// Layout returns the current column layout in display order
+func (t *Table) Layout() TableLayout {
+	layout := TableLayout{Columns: make([]ColumnLayout, 0, len(t.Columns))}
+	for _, i := range t.displayOrder() {
+		col := t.Columns[i]
+		layout.Columns = append(layout.Columns, ColumnLayout{
+			Title:  col.Title,
+			Width:  col.FixedWidth,
+			Hidden: col.Hidden,
+		})
+	}
+	return layout
+}
+
+// SetLayout restores a column layout. Columns are matched by title;
+// unknown titles are ignored and columns missing from the layout keep
+// their settings and are placed after the others.
+func (t *Table) SetLayout(layout TableLayout) {
+	order := make([]int, 0, len(t.Columns))
+	placed := make([]bool, len(t.Columns))
+	for _, saved := range layout.Columns {
+		for i := range t.Columns {
+			if placed[i] || t.Columns[i].Title != saved.Title {
+				continue
+			}
+			t.Columns[i].FixedWidth = saved.Width
+			t.Columns[i].Hidden = saved.Hidden
+			order = append(order, i)
+			placed[i] = true
+			break
+		}
+	}
+	for i := range t.Columns {
+		if !placed[i] {
+			order = append(order, i)
+		}
+	}
+	t.order = order
+
+	if len(t.Columns) > 0 && t.visibleColumnCount() == 0 {
+		t.Columns[order[0]].Hidden = false
+	}
+	if t.CurrentColumn >= 0 && t.CurrentColumn < len(t.Columns) && t.Columns[t.CurrentColumn].Hidden {
+		t.CurrentColumn = -1
+	}
+	t.adjustColumnWidths()
+}
+
+// LayoutJSON returns the column layout encoded as JSON
+func (t *Table) LayoutJSON() ([]byte, error) {
+	return json.Marshal(t.Layout())
+}
+
+// SetLayoutJSON restores a column layout encoded by LayoutJSON
+func (t *Table) SetLayoutJSON(data []byte) error {
+	var layout TableLayout
+	if err := json.Unmarshal(data, &layout); err != nil {
+		return err
+	}
+	t.SetLayout(layout)
+	return nil
+}
+
+// selectColumn moves CurrentColumn to the next visible column in display
+// order in the given direction, scrolling it into view. Without a current
+// column the first visible column is selected.
+func (t *Table) selectColumn(direction int) bool {
+	order := t.displayOrder()
+	pos := t.columnPosition(t.CurrentColumn)
+	if pos < 0 {
+		direction = 1
+	}
+	for pos += direction; pos >= 0 && pos < len(order); pos += direction {
+		if !t.Columns[order[pos]].Hidden {
+			t.CurrentColumn = order[pos]
+			t.scrollToColumn(pos)
+			return true
+		}
+	}
+	return false
+}
+
+// scrollToColumn scrolls horizontally until the column at the display
+// position is fully shown
+func (t *Table) scrollToColumn(pos int) {
+	if pos < t.frozenCount() {
+		return
+	}
+	if pos < t.ColumnOffset {
+		t.ColumnOffset = pos
+		return
+	}
+	for {
+		spans, _, hiddenRight := t.layoutColumns()
+		if len(spans) == 0 {
+			return
+		}
+		last := spans[len(spans)-1]
+		if last.pos > pos || (last.pos == pos && last.width == t.Columns[last.index].Width) {
+			return
+		}
+		if !hiddenRight || t.ColumnOffset >= pos || !t.ScrollColumns(1) {
+			return
+		}
+	}
+}
+
+// handleColumnKey handles the column key bindings: < and > select the
+// current column, Shift+Left and Shift+Right move it, - and + narrow and
+// widen it, Delete hides it and Insert shows all hidden columns. In tree
+// mode Shift+Left and Shift+Right move only a column already selected
+// with < or >, and otherwise scroll the columns.
+func (t *Table) handleColumnKey(ev *tcell.EventKey) bool {
+	switch ev.Key() {
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case '<':
+			return t.selectColumn(-1)
+		case '>':
+			return t.selectColumn(1)
+		case '-', '+', '=':
+			if t.CurrentColumn < 0 && !t.selectColumn(1) {
+				return false
+			}
+			width := t.Columns[t.CurrentColumn].Width + 1
+			if ev.Rune() == '-' {
+				width -= 2
+			}
+			if width < 1 {
+				return false
+			}
+			t.SetColumnWidth(t.CurrentColumn, width)
+			return true
+		}
+	case tcell.KeyLeft, tcell.KeyRight:
+		if ev.Modifiers()&tcell.ModShift == 0 {
+			return false
+		}
+		if t.CurrentColumn < 0 && (t.TreeMode() || !t.selectColumn(1)) {
+			return false
+		}
+		step := 1
+		if ev.Key() == tcell.KeyLeft {
+			step = -1
+		}
+		// Move past hidden columns to the next visible position
+		order := t.displayOrder()
+		from := t.columnPosition(t.CurrentColumn)
+		to := from + step
+		for to >= 0 && to < len(order) && t.Columns[order[to]].Hidden {
+			to += step
+		}
+		if to < 0 || to >= len(order) || !t.MoveColumn(t.CurrentColumn, to-from) {
+			return false
+		}
+		t.scrollToColumn(t.columnPosition(t.CurrentColumn))
+		return true
+	case tcell.KeyDelete:
+		if t.CurrentColumn < 0 || t.visibleColumnCount() == 1 {
+			return false
+		}
+		t.SetColumnHidden(t.CurrentColumn, true)
+		return true
+	case tcell.KeyInsert:
+		if t.visibleColumnCount() == len(t.Columns) {
+			return false
+		}
+		t.ShowAllColumns()
+		return true
+	}
+	return false
+}
+
+// headerY returns the screen row of the header, if it is shown
+func (t *Table) headerY() (int, bool) {
+	if !t.ShowHeader {
+		return 0, false
+	}
+	if t.ShowBorder {
+		return t.Y + 1, true
+	}
+	return t.Y, true
+}
+
+// HandleMouse handles mouse events on the header. Dragging a column
+// separator resizes the column to its left and dragging a title moves the
+// column. Clicking a title makes it the current column and sorts by it;
+// Shift+click adds it as a secondary sort key. Right-clicking a title hides
+// the column.
+func (t *Table) HandleMouse(ev *tcell.EventMouse) bool {
+	x, y := ev.Position()
+	buttons := ev.Buttons()
+
+	if t.drag.active {
+		if buttons&tcell.Button1 == 0 {
+			t.drag.active = false
+			if !t.drag.moved && !t.drag.resize {
+				t.CurrentColumn = t.drag.col
+				if t.drag.shift {
+					t.AddSortColumn(t.drag.col)
+				} else {
+					t.SetSortColumn(t.drag.col)
+				}
+			}
+			return true
+		}
+		return t.dragTo(x)
+	}
+
+	hy, ok := t.headerY()
+	if !ok || y != hy {
+		return false
+	}
+	spans, _, _ := t.layoutColumns()
+	for _, span := range spans {
+		switch {
+		case buttons&tcell.Button1 != 0 && x == span.x+span.width:
+			t.drag = columnDrag{active: true, resize: true, col: span.index, startX: x, startWidth: t.Columns[span.index].Width}
+			return true
+		case x < span.x || x >= span.x+span.width:
+			continue
+		case buttons&tcell.Button1 != 0:
+			t.drag = columnDrag{active: true, col: span.index, startX: x, shift: ev.Modifiers()&tcell.ModShift != 0}
+			return true
+		case buttons&tcell.Button2 != 0:
+			t.SetColumnHidden(span.index, true)
+			return true
+		}
+	}
+	return false
+}
+
+// dragTo continues a header drag at screen column x
+func (t *Table) dragTo(x int) bool {
+	if x == t.drag.startX && !t.drag.moved {
+		return false
+	}
+	t.drag.moved = true
+
+	if t.drag.resize {
+		width := t.drag.startWidth + x - t.drag.startX
+		if width < 1 {
+			width = 1
+		}
+		t.SetColumnWidth(t.drag.col, width)
+		return true
+	}
+
+	spans, _, _ := t.layoutColumns()
+	for _, span := range spans {
+		if x >= span.x && x < span.x+span.width && span.index != t.drag.col {
+			return t.MoveColumn(t.drag.col, span.pos-t.columnPosition(t.drag.col))
+		}
+	}
+	return true
+}
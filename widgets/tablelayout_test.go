@@ -0,0 +1,140 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func spanTitles(table *Table) []string {
+	spans, _, _ := table.layoutColumns()
+	titles := make([]string, len(spans))
+	for i, span := range spans {
+		titles[i] = table.Columns[span.index].Title
+	}
+	return titles
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTableColumnKeys(t *testing.T) {
+	t.Parallel()
+	table, _ := newProcessTable(t)
+
+	key := func(k tcell.Key, r rune, mod tcell.ModMask) bool {
+		return table.HandleEvent(tcell.NewEventKey(k, r, mod))
+	}
+
+	key(tcell.KeyRune, '>', tcell.ModNone)
+	key(tcell.KeyRune, '>', tcell.ModNone)
+	if table.CurrentColumn != 1 {
+		t.Fatalf("Expected User to be the current column, got %d", table.CurrentColumn)
+	}
+
+	key(tcell.KeyLeft, 0, tcell.ModShift)
+	if got := spanTitles(table); !equalStrings(got, []string{"User", "PID", "Command"}) {
+		t.Errorf("Expected User moved first, got %v", got)
+	}
+
+	width := table.Columns[1].Width
+	key(tcell.KeyRune, '+', tcell.ModNone)
+	if table.Columns[1].Width != width+1 || table.Columns[1].FixedWidth != width+1 {
+		t.Errorf("Expected + to widen User to %d, got %d", width+1, table.Columns[1].Width)
+	}
+
+	key(tcell.KeyDelete, 0, tcell.ModNone)
+	if got := spanTitles(table); !equalStrings(got, []string{"PID", "Command"}) {
+		t.Errorf("Expected User hidden, got %v", got)
+	}
+	if table.CurrentColumn != 0 {
+		t.Errorf("Expected the next column to become current, got %d", table.CurrentColumn)
+	}
+	// Only the hidden User column is left of PID
+	if key(tcell.KeyLeft, 0, tcell.ModShift) {
+		t.Error("Expected PID not to move past hidden columns only")
+	}
+
+	key(tcell.KeyInsert, 0, tcell.ModNone)
+	if got := spanTitles(table); len(got) != 3 {
+		t.Errorf("Expected Insert to show all columns, got %v", got)
+	}
+}
+
+func TestTableLayoutJSON(t *testing.T) {
+	t.Parallel()
+	table, _ := newProcessTable(t)
+	table.MoveColumn(2, -2)
+	table.SetColumnWidth(2, 20)
+	table.SetColumnHidden(0, true)
+
+	data, err := table.LayoutJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"columns":[{"title":"Command","width":20},{"title":"PID","hidden":true},{"title":"User"}]}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+
+	restored, _ := newProcessTable(t)
+	if err := restored.SetLayoutJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	if got := spanTitles(restored); !equalStrings(got, []string{"Command", "User"}) {
+		t.Errorf("Expected restored order Command, User, got %v", got)
+	}
+	if restored.Columns[2].Width != 20 {
+		t.Errorf("Expected restored width 20, got %d", restored.Columns[2].Width)
+	}
+	if err := restored.SetLayoutJSON([]byte("{")); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+}
+
+func TestTableHeaderMouse(t *testing.T) {
+	t.Parallel()
+	table, _ := newProcessTable(t)
+	mouse := func(x int, buttons tcell.ButtonMask) bool {
+		return table.HandleMouse(tcell.NewEventMouse(x, 1, buttons, tcell.ModNone))
+	}
+
+	spans, _, _ := table.layoutColumns()
+	sep := spans[0].x + spans[0].width
+	width := table.Columns[0].Width
+	mouse(sep, tcell.Button1)
+	mouse(sep+3, tcell.Button1)
+	mouse(sep+3, tcell.ButtonNone)
+	if table.Columns[0].Width != width+3 {
+		t.Errorf("Expected dragging the separator to widen PID to %d, got %d", width+3, table.Columns[0].Width)
+	}
+
+	spans, _, _ = table.layoutColumns()
+	mouse(spans[2].x, tcell.Button1)
+	mouse(spans[0].x, tcell.Button1)
+	mouse(spans[0].x, tcell.ButtonNone)
+	if got := spanTitles(table); !equalStrings(got, []string{"Command", "PID", "User"}) {
+		t.Errorf("Expected dragging Command to the front, got %v", got)
+	}
+
+	spans, _, _ = table.layoutColumns()
+	mouse(spans[2].x, tcell.Button1)
+	mouse(spans[2].x, tcell.ButtonNone)
+	if table.CurrentColumn != 1 {
+		t.Errorf("Expected clicking User to make it current, got %d", table.CurrentColumn)
+	}
+
+	mouse(spans[1].x, tcell.Button2)
+	if got := spanTitles(table); !equalStrings(got, []string{"Command", "User"}) {
+		t.Errorf("Expected right-click to hide PID, got %v", got)
+	}
+}
@@ -0,0 +1,142 @@
+package widgets
+
+// TableModel supplies rows to a Table on demand. The table only reads the
+// rows it draws, so a model can back millions of rows without holding them
+// all in memory.
+type TableModel interface {
+	// RowCount returns the number of rows
+	RowCount() int
+	// Row returns the cells of row i
+	Row(i int) []string
+}
+
+// SortableModel is a TableModel that can sort its rows, e.g. by issuing an
+// ORDER BY query. Tables backed by models that don't implement it show the
+// rows in model order.
+type SortableModel interface {
+	TableModel
+	// Sort orders the rows by the given column
+	Sort(col int, ascending bool)
+}
+
+// SetModel makes the table read its rows from model instead of Rows. Pass
+// nil to go back to Rows. Filtering a model reads every row once per
+// change of the filter.
+func (t *Table) SetModel(model TableModel) {
+	t.Model = model
+	t.tree = nil
+	t.treeNodes = nil
+	t.checked = nil
+	t.SelectedRow = 0
+	t.ScrollOffset = 0
+	t.Refresh()
+}
+
+// Refresh rereads the rows after they changed, reapplying sorting and
+// filtering. Call it after the model's data changes or after editing Rows
+// directly.
+func (t *Table) Refresh() {
+	t.measureRows()
+	t.dirty = true
+	t.flush()
+	t.followEdit()
+}
+
+// flush sorts, filters and measures rows added since the last flush. Row
+// changes are batched this way so adding many rows stays cheap.
+func (t *Table) flush() {
+	if !t.dirty {
+		return
+	}
+	t.dirty = false
+	selected, anchor := t.SelectedRow, t.anchor
+	if t.SortColumn >= 0 {
+		t.sort()
+	}
+	t.refreshView()
+	t.adjustColumnWidths()
+
+	// Follow the selected row unless the selection was moved directly
+	if anchor.valid && anchor.index == selected && !t.followAnchor(anchor) {
+		t.scrollToSelected()
+	}
+	t.captureAnchor()
+}
+
+// sourceCount returns the number of rows before filtering
+func (t *Table) sourceCount() int {
+	if t.Model != nil {
+		return t.Model.RowCount()
+	}
+	return len(t.Rows)
+}
+
+// sourceRow returns row i before filtering
+func (t *Table) sourceRow(i int) []string {
+	if t.Model != nil {
+		return t.Model.Row(i)
+	}
+	return t.Rows[i]
+}
+
+// canSort returns whether the rows can be sorted
+func (t *Table) canSort() bool {
+	if t.Model == nil {
+		return true
+	}
+	_, ok := t.Model.(SortableModel)
+	return ok
+}
+
+// measureRows recomputes the widest value of each column. Model rows are
+// measured as they are drawn instead.
+func (t *Table) measureRows() {
+	t.contentWidths = make([]int, len(t.Columns))
+	if t.Model == nil {
+		for _, row := range t.Rows {
+			t.measureRow(row)
+		}
+	}
+	t.measuredTreeColumn = t.treeColumn()
+	for _, node := range t.treeNodes {
+		t.measureTreeRow(node)
+	}
+	if t.footer != nil {
+		t.measureRow(t.footer)
+	}
+}
+
+// measureRow widens the measured columns to fit row. It returns whether
+// any column grew.
+func (t *Table) measureRow(row []string) bool {
+	if len(t.contentWidths) != len(t.Columns) {
+		t.measureRows()
+	}
+	grew := false
+	for i := range t.contentWidths {
+		if w := textWidth(cellValue(row, i)); w > t.contentWidths[i] {
+			t.contentWidths[i] = w
+			grew = true
+		}
+	}
+	return grew
+}
+
+// measureVisible measures the model rows about to be drawn, so columns
+// widen as wider values scroll into view
+func (t *Table) measureVisible() {
+	if t.Model == nil {
+		return
+	}
+	end := t.ScrollOffset + t.visibleRowCount()
+	if count := t.rowCount(); end > count {
+		end = count
+	}
+	grew := false
+	for i := t.ScrollOffset; i < end; i++ {
+		grew = t.measureRow(t.displayRow(i)) || grew
+	}
+	if grew {
+		t.adjustColumnWidths()
+	}
+}
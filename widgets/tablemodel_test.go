@@ -0,0 +1,97 @@
+package widgets
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// countingModel generates rows on demand and records which were read
+type countingModel struct {
+	count     int
+	reads     map[int]bool
+	sortCol   int
+	ascending bool
+}
+
+func (m *countingModel) RowCount() int {
+	return m.count
+}
+
+func (m *countingModel) Row(i int) []string {
+	m.reads[i] = true
+	n := i
+	if !m.ascending {
+		n = m.count - 1 - i
+	}
+	return []string{strconv.Itoa(n), fmt.Sprintf("line %d", n)}
+}
+
+func (m *countingModel) Sort(col int, ascending bool) {
+	m.sortCol = col
+	m.ascending = ascending
+}
+
+func TestTableModel(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(40, 10)
+
+	model := &countingModel{count: 1000000, reads: map[int]bool{}, ascending: true}
+	table := NewTable(screen, 0, 0, 30, 8)
+	table.SortColumn = -1
+	table.SetColumns([]Column{{Title: "N", Type: ColumnInt}, {Title: "Text"}})
+	table.SetModel(model)
+	table.Draw()
+
+	if len(model.reads) > table.visibleRowCount() {
+		t.Errorf("Expected only the visible rows to be read, got %d reads", len(model.reads))
+	}
+	if table.Columns[1].Width < len("line 0") {
+		t.Errorf("Expected columns to fit the drawn rows, got width %d", table.Columns[1].Width)
+	}
+
+	table.HandleEvent(tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModNone))
+	row, idx := table.GetSelectedRow()
+	if idx != 999999 || row[0] != "999999" {
+		t.Errorf("Expected the last row, got %v at %d", row, idx)
+	}
+
+	table.SetSortColumn(0)
+	table.SetSortColumn(0)
+	if model.ascending {
+		t.Error("Expected the model to be sorted descending")
+	}
+	if row := table.displayRow(0); row[0] != "999999" {
+		t.Errorf("Expected the model order after sorting, got %v", row)
+	}
+}
+
+func TestTableAddRowBatched(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	table := NewTable(screen, 0, 0, 40, 10)
+	table.SetColumns([]Column{{Title: "N", Type: ColumnInt}, {Title: "Text"}})
+	table.SortColumn = 0
+	table.SortAscending = true
+	for i := 100000; i > 0; i-- {
+		table.AddRow([]string{strconv.Itoa(i), "line"})
+	}
+
+	row, _ := table.GetSelectedRow()
+	if row[0] != "1" {
+		t.Errorf("Expected added rows to be sorted before use, got %v first", row)
+	}
+	if table.Columns[0].Width < len("100000") {
+		t.Errorf("Expected the widest value to be measured, got width %d", table.Columns[0].Width)
+	}
+}
@@ -0,0 +1,118 @@
+package widgets
+
+// rowAnchor remembers which logical row was selected and where it was on
+// screen
+type rowAnchor struct {
+	key    string
+	index  int // selected display index when captured
+	offset int // selected row's distance from the top of the view
+	valid  bool
+}
+
+// SetRowKey sets the function that identifies rows across updates, e.g.
+// KeyColumn(0) for a PID column. With a row key, the selection, scroll
+// position and checked rows follow the same logical row when rows are
+// replaced, sorted or filtered. If the selected row disappears, the row
+// that took its place is selected. Finding the row reads every row, so
+// with a Model only set a row key if rows are cheap to read.
+func (t *Table) SetRowKey(fn func(row []string) string) {
+	t.RowKey = fn
+	t.captureAnchor()
+}
+
+// KeyColumn returns a row key function that uses the value in col
+func KeyColumn(col int) func(row []string) string {
+	return func(row []string) string {
+		return cellValue(row, col)
+	}
+}
+
+// SelectKey selects the visible row with the given key. It returns false
+// if there is no row key function or no such row.
+func (t *Table) SelectKey(key string) bool {
+	idx := t.findKey(key)
+	if idx < 0 {
+		return false
+	}
+	t.SelectedRow = idx
+	t.scrollToSelected()
+	t.captureAnchor()
+	return true
+}
+
+// findKey returns the display index of the row with the given key, or -1
+func (t *Table) findKey(key string) int {
+	if t.RowKey == nil {
+		return -1
+	}
+	for i := 0; i < t.rowCount(); i++ {
+		if t.RowKey(t.displayRow(i)) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// captureAnchor remembers the selected row so it can be found again after
+// the rows change
+func (t *Table) captureAnchor() {
+	t.anchor = t.currentAnchor()
+}
+
+// currentAnchor returns the anchor for the selected row
+func (t *Table) currentAnchor() rowAnchor {
+	if t.RowKey == nil || t.SelectedRow < 0 || t.SelectedRow >= t.rowCount() {
+		return rowAnchor{}
+	}
+	return rowAnchor{
+		key:    t.RowKey(t.displayRow(t.SelectedRow)),
+		index:  t.SelectedRow,
+		offset: t.SelectedRow - t.ScrollOffset,
+		valid:  true,
+	}
+}
+
+// followAnchor selects the row the anchor points to and restores its
+// position on screen. It returns false if the row is gone.
+func (t *Table) followAnchor(anchor rowAnchor) bool {
+	if !anchor.valid {
+		return false
+	}
+	idx := t.findKey(anchor.key)
+	if idx < 0 {
+		return false
+	}
+	t.SelectedRow = idx
+	t.ScrollOffset = idx - anchor.offset
+	t.clampSelection()
+	t.scrollToSelected()
+	return true
+}
+
+// checkedKeys returns the keys of the checked rows
+func (t *Table) checkedKeys() map[string]bool {
+	if t.RowKey == nil || len(t.checked) == 0 {
+		return nil
+	}
+	keys := make(map[string]bool, len(t.checked))
+	for i := range t.checked {
+		if i < t.sourceCount() {
+			keys[t.RowKey(t.sourceRow(i))] = true
+		}
+	}
+	return keys
+}
+
+// checkKeys checks the rows whose keys are in keys
+func (t *Table) checkKeys(keys map[string]bool) {
+	t.checked = nil
+	if len(keys) == 0 {
+		return
+	}
+	t.checked = make(map[int]bool, len(keys))
+	for i := 0; i < t.sourceCount(); i++ {
+		if keys[t.RowKey(t.sourceRow(i))] {
+			t.checked[i] = true
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestTableRowKeySelection(t *testing.T) {
+	t.Parallel()
+	table, _ := newProcessTable(t)
+	table.SetRowKey(KeyColumn(0))
+	table.SetMultiSelect(true)
+
+	// Select nginx-worker (201) and check alice's vim (300)
+	table.SelectKey("201")
+	table.SetRowChecked(3, true)
+
+	// Fresh data in a different order, with a new row in front
+	table.SetRows([][]string{
+		{"301", "root", "sshd"},
+		{"300", "alice", "vim"},
+		{"201", "www", "nginx-worker"},
+		{"100", "bob", "bash"},
+		{"1", "root", "init"},
+	})
+	row, idx := table.GetSelectedRow()
+	if row[0] != "201" || idx != 2 {
+		t.Errorf("Expected selection to follow PID 201, got %v at %d", row, idx)
+	}
+	if got := checkedPIDs(table); len(got) != 1 || got[0] != "300" {
+		t.Errorf("Expected PID 300 to stay checked, got %v", got)
+	}
+
+	// Sorting keeps the same row selected
+	table.SetSortColumn(0)
+	if row, _ := table.GetSelectedRow(); row[0] != "201" {
+		t.Errorf("Expected selection to follow PID 201 after sorting, got %v", row)
+	}
+
+	// Filtering keeps the selected row if it still matches
+	if err := table.SetFilter("www"); err != nil {
+		t.Fatal(err)
+	}
+	if row, _ := table.GetSelectedRow(); row[0] != "201" {
+		t.Errorf("Expected selection to follow PID 201 when filtering, got %v", row)
+	}
+	table.ClearFilter()
+
+	// When the row disappears, the row that took its place is selected
+	_, idx = table.GetSelectedRow()
+	table.SetRows([][]string{
+		{"1", "root", "init"},
+		{"100", "bob", "bash"},
+		{"300", "alice", "vim"},
+		{"301", "root", "sshd"},
+	})
+	row, newIdx := table.GetSelectedRow()
+	if newIdx != idx || row[0] != "300" {
+		t.Errorf("Expected fallback to the row at index %d, got %v at %d", idx, row, newIdx)
+	}
+}
+
+func TestTableRowKeyScroll(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := func(first int) [][]string {
+		var rows [][]string
+		for i := first; i < first+20; i++ {
+			rows = append(rows, []string{string(rune('a' + i))})
+		}
+		return rows
+	}
+	table := NewTable(screen, 0, 0, 20, 6)
+	table.SortColumn = -1
+	table.SetColumns([]Column{{Title: "Key"}})
+	table.SetRowKey(KeyColumn(0))
+	table.SetRows(rows(0))
+	for i := 0; i < 10; i++ {
+		table.HandleEvent(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone))
+	}
+	offset := table.SelectedRow - table.ScrollOffset
+
+	// Two rows disappear from the top
+	table.SetRows(rows(2))
+	if row, _ := table.GetSelectedRow(); row[0] != "k" {
+		t.Errorf("Expected row k to stay selected, got %v", row)
+	}
+	if got := table.SelectedRow - table.ScrollOffset; got != offset {
+		t.Errorf("Expected the selected row to stay %d lines from the top, got %d", offset, got)
+	}
+}
@@ -0,0 +1,166 @@
+package widgets
+
+import (
+	"github.com/deadjoe/termdodo/symbols"
+	"github.com/gdamore/tcell/v2"
+)
+
+// markerWidth is the width of the checked-row marker column
+const markerWidth = 2
+
+// SetMultiSelect sets whether rows can be checked. Checked rows are marked
+// in a narrow column before the first column.
+func (t *Table) SetMultiSelect(enabled bool) {
+	t.MultiSelect = enabled
+	if !enabled {
+		t.ClearChecked()
+	}
+	t.adjustColumnWidths()
+}
+
+// markerOffset returns the width reserved for the marker column
+func (t *Table) markerOffset() int {
+	if t.MultiSelect {
+		return markerWidth
+	}
+	return 0
+}
+
+// IsRowChecked returns whether the visible row at index i is checked
+func (t *Table) IsRowChecked(i int) bool {
+	if i < 0 || i >= t.rowCount() {
+		return false
+	}
+	return t.checked[t.rowIndex(i)]
+}
+
+// SetRowChecked checks or unchecks the visible row at index i
+func (t *Table) SetRowChecked(i int, checked bool) {
+	if i < 0 || i >= t.rowCount() {
+		return
+	}
+	if checked {
+		if t.checked == nil {
+			t.checked = make(map[int]bool)
+		}
+		t.checked[t.rowIndex(i)] = true
+	} else {
+		delete(t.checked, t.rowIndex(i))
+	}
+}
+
+// ToggleRow flips whether the visible row at index i is checked
+func (t *Table) ToggleRow(i int) {
+	t.SetRowChecked(i, !t.IsRowChecked(i))
+}
+
+// CheckAll checks every visible row
+func (t *Table) CheckAll() {
+	for i := 0; i < t.rowCount(); i++ {
+		t.SetRowChecked(i, true)
+	}
+}
+
+// InvertChecked flips the checked state of every visible row
+func (t *Table) InvertChecked() {
+	for i := 0; i < t.rowCount(); i++ {
+		t.ToggleRow(i)
+	}
+}
+
+// ClearChecked unchecks all rows, including those hidden by a filter
+func (t *Table) ClearChecked() {
+	t.checked = nil
+}
+
+// CheckedCount returns the number of checked rows, including those hidden
+// by a filter
+func (t *Table) CheckedCount() int {
+	return len(t.checked)
+}
+
+// GetSelectedRows returns the checked rows in display order. Rows hidden by
+// a filter stay checked but are not returned. Without checked rows the
+// row under the cursor is returned, so callers can always act on the
+// result.
+func (t *Table) GetSelectedRows() [][]string {
+	var rows [][]string
+	for i := 0; i < t.rowCount(); i++ {
+		if t.IsRowChecked(i) {
+			rows = append(rows, t.displayRow(i))
+		}
+	}
+	if len(rows) == 0 {
+		if row, idx := t.GetSelectedRow(); idx >= 0 {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// applyOrder reorders Rows in place so that row i is the former row
+// order[i], keeping checked rows checked
+func (t *Table) applyOrder(order []int) {
+	rows := make([][]string, len(order))
+	var checked map[int]bool
+	editSrc := t.edit.src
+	for i, from := range order {
+		rows[i] = t.Rows[from]
+		if from == editSrc {
+			t.edit.src = i // the editor moves with its row
+		}
+		if t.checked[from] {
+			if checked == nil {
+				checked = make(map[int]bool, len(t.checked))
+			}
+			checked[i] = true
+		}
+	}
+	copy(t.Rows, rows)
+	t.checked = checked
+}
+
+// handleSelectKey handles the multi-select key bindings: Space toggles the
+// selected row, Shift+Up and Shift+Down extend the checked rows, Ctrl+A
+// checks all rows and * inverts the checked rows.
+func (t *Table) handleSelectKey(ev *tcell.EventKey) bool {
+	switch ev.Key() {
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case ' ':
+			t.ToggleRow(t.SelectedRow)
+			return true
+		case '*':
+			t.InvertChecked()
+			return true
+		}
+	case tcell.KeyCtrlA:
+		t.CheckAll()
+		return true
+	case tcell.KeyUp, tcell.KeyDown:
+		if ev.Modifiers()&tcell.ModShift == 0 {
+			return false
+		}
+		t.SetRowChecked(t.SelectedRow, true)
+		if ev.Key() == tcell.KeyUp {
+			t.handleUpKey()
+		} else {
+			t.handleDownKey()
+		}
+		t.SetRowChecked(t.SelectedRow, true)
+		return true
+	}
+	return false
+}
+
+// drawMarker draws the checked-row marker for the visible row at index i
+func (t *Table) drawMarker(i, y int, style tcell.Style) {
+	left, _ := t.innerBounds()
+	r := ' '
+	if t.IsRowChecked(i) {
+		r = symbols.Check
+		style = t.CheckedStyle
+	}
+	t.Screen.SetContent(left, y, r, nil, style)
+	t.Screen.SetContent(left+1, y, ' ', nil, style)
+}
@@ -0,0 +1,83 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/deadjoe/termdodo/symbols"
+	"github.com/gdamore/tcell/v2"
+)
+
+func checkedPIDs(table *Table) []string {
+	var pids []string
+	for _, row := range table.GetSelectedRows() {
+		pids = append(pids, row[0])
+	}
+	return pids
+}
+
+func TestTableMultiSelect(t *testing.T) {
+	t.Parallel()
+	table, screen := newProcessTable(t)
+	table.SetMultiSelect(true)
+
+	if got := checkedPIDs(table); len(got) != 1 || got[0] != "1" {
+		t.Errorf("Expected the cursor row without checked rows, got %v", got)
+	}
+
+	space := tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone)
+	table.HandleEvent(space)
+	table.HandleEvent(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModShift))
+	if got := checkedPIDs(table); len(got) != 2 || got[0] != "1" || got[1] != "200" {
+		t.Errorf("Expected rows 1 and 200 checked, got %v", got)
+	}
+
+	table.HandleEvent(space)
+	if table.CheckedCount() != 1 {
+		t.Errorf("Expected Space to uncheck row 200, got %d checked", table.CheckedCount())
+	}
+
+	table.Draw()
+	if r, _, _, _ := screen.GetContent(1, 2); r != symbols.Check {
+		t.Errorf("Expected a check mark on the first row, got %q", r)
+	}
+	if spans, _, _ := table.layoutColumns(); spans[0].x != 3 {
+		t.Errorf("Expected columns to start after the marker, got x=%d", spans[0].x)
+	}
+
+	table.HandleEvent(tcell.NewEventKey(tcell.KeyRune, '*', tcell.ModNone))
+	if table.CheckedCount() != 4 || table.IsRowChecked(0) {
+		t.Errorf("Expected * to invert the checked rows, got %d checked", table.CheckedCount())
+	}
+
+	table.ClearChecked()
+	table.HandleEvent(tcell.NewEventKey(tcell.KeyCtrlA, 0, tcell.ModCtrl))
+	if table.CheckedCount() != 5 {
+		t.Errorf("Expected Ctrl+A to check all rows, got %d", table.CheckedCount())
+	}
+}
+
+func TestTableCheckedSurvivesSort(t *testing.T) {
+	t.Parallel()
+	table, _ := newProcessTable(t)
+	table.SetMultiSelect(true)
+	table.SetRowChecked(1, true) // 200
+	table.SetRowChecked(4, true) // 301
+
+	table.SortColumn = -1
+	table.SetSortColumn(0)
+	table.SetSortColumn(0) // descending
+	if got := checkedPIDs(table); len(got) != 2 || got[0] != "301" || got[1] != "200" {
+		t.Errorf("Expected checked rows to follow sorting, got %v", got)
+	}
+
+	if err := table.SetFilter("www"); err != nil {
+		t.Fatal(err)
+	}
+	if got := checkedPIDs(table); len(got) != 1 || got[0] != "200" {
+		t.Errorf("Expected only visible checked rows, got %v", got)
+	}
+	table.ClearFilter()
+	if table.CheckedCount() != 2 {
+		t.Errorf("Expected hidden rows to stay checked, got %d", table.CheckedCount())
+	}
+}
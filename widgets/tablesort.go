@@ -0,0 +1,169 @@
+package widgets
+
+import (
+	"github.com/deadjoe/termdodo/symbols"
+	"github.com/gdamore/tcell/v2"
+)
+
+// SortKey is one column rows are ordered by
+type SortKey struct {
+	Column    int
+	Ascending bool
+}
+
+// SortKeys returns the primary sort key followed by the secondary ones
+func (t *Table) SortKeys() []SortKey {
+	if t.SortColumn < 0 || t.SortColumn >= len(t.Columns) {
+		return nil
+	}
+	keys := []SortKey{{Column: t.SortColumn, Ascending: t.SortAscending}}
+	for _, key := range t.ThenBy {
+		if key.Column >= 0 && key.Column < len(t.Columns) && key.Column != t.SortColumn {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// sortValues parses the cells of every row for the given sort keys once,
+// indexed by source row and then by key
+func (t *Table) sortValues(keys []SortKey) [][]sortValue {
+	values := make([][]sortValue, len(t.Rows))
+	for i, row := range t.Rows {
+		values[i] = make([]sortValue, len(keys))
+		for k, key := range keys {
+			values[i][k] = t.Columns[key.Column].sortValue(cellValue(row, key.Column))
+		}
+	}
+	return values
+}
+
+// lessValues returns whether the row with values a sorts before the row
+// with values b, both from sortValues for the same keys
+func (t *Table) lessValues(keys []SortKey, a, b []sortValue) bool {
+	for k, key := range keys {
+		if c := t.Columns[key.Column].sortCompare(a[k], b[k], key.Ascending); c != 0 {
+			return c < 0
+		}
+	}
+	return false
+}
+
+// SetSortKeys sorts by the given keys, the first being the primary key.
+// Passing no keys turns sorting off.
+func (t *Table) SetSortKeys(keys ...SortKey) {
+	if len(keys) == 0 {
+		t.SortColumn = -1
+		t.ThenBy = nil
+		return
+	}
+	t.SortColumn = keys[0].Column
+	t.SortAscending = keys[0].Ascending
+	t.ThenBy = append([]SortKey(nil), keys[1:]...)
+	t.captureAnchor()
+	t.dirty = true
+	t.flush()
+}
+
+// AddSortColumn adds col as a secondary sort key, or reverses its
+// direction if it already is a sort key
+func (t *Table) AddSortColumn(col int) {
+	if !t.Sortable || col < 0 || col >= len(t.Columns) {
+		return
+	}
+	if t.SortColumn < 0 || t.SortColumn == col {
+		t.SetSortColumn(col)
+		return
+	}
+
+	keys := t.SortKeys()
+	found := false
+	for i := range keys {
+		if keys[i].Column == col {
+			keys[i].Ascending = !keys[i].Ascending
+			found = true
+		}
+	}
+	if !found {
+		keys = append(keys, SortKey{Column: col, Ascending: true})
+	}
+	t.SetSortKeys(keys...)
+}
+
+// CycleSortColumn makes the next visible column in display order the sort
+// column, or the previous one if direction is negative
+func (t *Table) CycleSortColumn(direction int) bool {
+	if !t.Sortable || !t.canSort() || t.visibleColumnCount() == 0 {
+		return false
+	}
+	step := 1
+	if direction < 0 {
+		step = -1
+	}
+	order := t.displayOrder()
+	pos := t.columnPosition(t.SortColumn)
+	if pos < 0 && step < 0 {
+		pos = len(order)
+	}
+	for {
+		pos = (pos + step + len(order)) % len(order)
+		if !t.Columns[order[pos]].Hidden {
+			break
+		}
+	}
+	t.SortColumn = -1 // always sort ascending by the new column
+	t.SetSortColumn(order[pos])
+	return true
+}
+
+// ReverseSort reverses the direction of the primary sort key
+func (t *Table) ReverseSort() bool {
+	if !t.Sortable || t.SortColumn < 0 {
+		return false
+	}
+	t.SetSortColumn(t.SortColumn)
+	return true
+}
+
+// sortIndicator returns the text shown after the title of a sorted column.
+// With several sort keys the secondary ones are numbered.
+func (t *Table) sortIndicator(col int) string {
+	if !t.Sortable || !t.canSort() {
+		return ""
+	}
+	keys := t.SortKeys()
+	if t.Model != nil && len(keys) > 1 {
+		keys = keys[:1]
+	}
+	for rank, key := range keys {
+		if key.Column != col {
+			continue
+		}
+		indicator := string(symbols.TriangleDown)
+		if key.Ascending {
+			indicator = string(symbols.TriangleUp)
+		}
+		if len(keys) > 1 && rank+1 < len(symbols.SuperScript) {
+			indicator += symbols.SuperScript[rank+1]
+		}
+		return indicator
+	}
+	return ""
+}
+
+// handleSortKey handles the sort key bindings: s and S cycle the sort
+// column forwards and backwards and r reverses the sort direction
+func (t *Table) handleSortKey(ev *tcell.EventKey) bool {
+	if ev.Key() != tcell.KeyRune {
+		return false
+	}
+	switch ev.Rune() {
+	case 's':
+		return t.CycleSortColumn(1)
+	case 'S':
+		return t.CycleSortColumn(-1)
+	case 'r':
+		return t.ReverseSort()
+	}
+	return false
+}
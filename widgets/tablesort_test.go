@@ -0,0 +1,129 @@
+package widgets
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/deadjoe/termdodo/symbols"
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestTableMultiKeySort(t *testing.T) {
+	t.Parallel()
+	table, screen := newProcessTable(t)
+	table.SetSortKeys(SortKey{Column: 1, Ascending: true}, SortKey{Column: 0, Ascending: false})
+
+	var got []string
+	for i := 0; i < table.rowCount(); i++ {
+		got = append(got, table.displayRow(i)[0])
+	}
+	if want := []string{"300", "301", "1", "201", "200"}; !equalStrings(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	table.Draw()
+	header := readRow(screen, 1, 1, 28)
+	if !strings.Contains(header, "User"+string(symbols.TriangleUp)+symbols.SuperScript[1]) {
+		t.Errorf("Expected a numbered ascending indicator on User, got %q", header)
+	}
+	if !strings.Contains(header, string(symbols.TriangleDown)+symbols.SuperScript[2]) {
+		t.Errorf("Expected a numbered descending indicator on PID, got %q", header)
+	}
+
+	table.AddSortColumn(0)
+	if keys := table.SortKeys(); len(keys) != 2 || !keys[1].Ascending {
+		t.Errorf("Expected AddSortColumn to reverse the secondary key, got %+v", keys)
+	}
+	table.SetSortColumn(2)
+	if keys := table.SortKeys(); len(keys) != 1 || keys[0].Column != 2 {
+		t.Errorf("Expected SetSortColumn to clear secondary keys, got %+v", keys)
+	}
+}
+
+func TestTableSortValues(t *testing.T) {
+	t.Parallel()
+	table := NewTable(createTestScreen(t), 0, 0, 30, 10)
+	table.SortColumn = -1
+	table.SetColumns([]Column{{Title: "Size", Type: ColumnBytes}, {Title: "Name"}})
+	table.SetRows([][]string{{"2K", "b"}, {"n/a", "a"}, {"512", "c"}})
+
+	keys := []SortKey{{Column: 0, Ascending: false}, {Column: 1, Ascending: true}}
+	values := table.sortValues(keys)
+	if len(values) != 3 || len(values[0]) != 2 {
+		t.Fatalf("Expected one value per row and key, got %v", values)
+	}
+	if v := values[0][0]; !v.parsed || v.number != 2048 {
+		t.Errorf("Expected 2K parsed as 2048, got %+v", v)
+	}
+	if v := values[1][0]; v.parsed || v.text != "n/a" {
+		t.Errorf("Expected n/a left unparsed, got %+v", v)
+	}
+	if v := values[0][1]; v.parsed || v.text != "b" {
+		t.Errorf("Expected text columns left unparsed, got %+v", v)
+	}
+
+	// Unparsable values stay last in descending order
+	table.SetSortKeys(keys...)
+	var got []string
+	for i := 0; i < table.rowCount(); i++ {
+		got = append(got, table.displayRow(i)[0])
+	}
+	if want := []string{"2K", "512", "n/a"}; !equalStrings(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestTableSortKeys(t *testing.T) {
+	t.Parallel()
+	table, screen := newProcessTable(t)
+
+	key := func(r rune) {
+		table.HandleEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	key('s')
+	if table.SortColumn != 0 || !table.SortAscending {
+		t.Errorf("Expected s to sort by PID ascending, got %d %v", table.SortColumn, table.SortAscending)
+	}
+	key('s')
+	key('S')
+	key('S')
+	if table.SortColumn != 2 {
+		t.Errorf("Expected S to cycle backwards to Command, got %d", table.SortColumn)
+	}
+	key('r')
+	if table.SortAscending {
+		t.Error("Expected r to reverse the sort direction")
+	}
+
+	// A narrow column keeps its indicator
+	table.SetColumnWidth(2, 4)
+	table.Draw()
+	spans, _, _ := table.layoutColumns()
+	last := spans[len(spans)-1]
+	if r, _, _, _ := screen.GetContent(last.x+3, 1); r != symbols.TriangleDown {
+		t.Errorf("Expected the indicator in the last cell of a clipped title, got %q", r)
+	}
+}
+
+func TestTableHeaderClickSort(t *testing.T) {
+	t.Parallel()
+	table, _ := newProcessTable(t)
+	click := func(x int, mod tcell.ModMask) {
+		table.HandleMouse(tcell.NewEventMouse(x, 1, tcell.Button1, mod))
+		table.HandleMouse(tcell.NewEventMouse(x, 1, tcell.ButtonNone, mod))
+	}
+
+	spans, _, _ := table.layoutColumns()
+	click(spans[1].x, tcell.ModNone)
+	if table.SortColumn != 1 || !table.SortAscending {
+		t.Errorf("Expected clicking User to sort by it, got %d", table.SortColumn)
+	}
+	click(spans[0].x, tcell.ModShift)
+	if keys := table.SortKeys(); len(keys) != 2 || keys[1].Column != 0 {
+		t.Errorf("Expected Shift+click to add PID as a secondary key, got %+v", keys)
+	}
+	click(spans[1].x, tcell.ModNone)
+	if table.SortAscending {
+		t.Error("Expected clicking the sort column again to reverse it")
+	}
+}
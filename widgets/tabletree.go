@@ -0,0 +1,319 @@
+package widgets
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/deadjoe/termdodo/symbols"
+	"github.com/gdamore/tcell/v2"
+)
+
+// TableNode is a row of a tree-table. Like TreeNode, it has a parent and
+// children that are shown indented below it while it is expanded.
+type TableNode struct {
+	Cells    []string
+	Children []*TableNode
+	Parent   *TableNode
+	Expanded bool
+	Data     interface{} // Optional data associated with the node
+
+	index int // index into Rows
+	depth int
+}
+
+// SetTree shows the given nodes and their descendants as a tree-table.
+// The first visible column is indented and drawn with guides, sorting
+// orders siblings, and filtering keeps the ancestors of matching rows.
+// With a row key, nodes keep their expanded and checked state across
+// calls. SetRows leaves tree mode.
+func (t *Table) SetTree(roots ...*TableNode) {
+	t.captureAnchor()
+	keys := t.checkedKeys()
+	expanded := t.expandedKeys()
+
+	t.Model = nil
+	t.tree = roots
+	t.Rows = nil
+	t.treeNodes = nil
+	var index func(nodes []*TableNode, parent *TableNode, depth int)
+	index = func(nodes []*TableNode, parent *TableNode, depth int) {
+		for _, node := range nodes {
+			node.Parent = parent
+			node.index = len(t.Rows)
+			node.depth = depth
+			if expanded != nil && expanded[t.RowKey(node.Cells)] {
+				node.Expanded = true
+			}
+			t.Rows = append(t.Rows, node.Cells)
+			t.treeNodes = append(t.treeNodes, node)
+			index(node.Children, node, depth+1)
+		}
+	}
+	index(roots, nil, 0)
+
+	t.checkKeys(keys)
+	t.Refresh()
+}
+
+// AddNode adds a node with the given cells below parent, or as a new root
+// if parent is nil. It switches the table to tree mode if needed.
+func (t *Table) AddNode(parent *TableNode, cells []string) *TableNode {
+	if t.tree == nil && len(t.Rows) > 0 {
+		// Keep existing rows as roots
+		roots := make([]*TableNode, len(t.Rows))
+		for i, row := range t.Rows {
+			roots[i] = &TableNode{Cells: row}
+		}
+		t.SetTree(roots...)
+	}
+
+	node := &TableNode{Cells: cells, Parent: parent, index: len(t.Rows)}
+	if parent == nil {
+		t.tree = append(t.tree, node)
+	} else {
+		node.depth = parent.depth + 1
+		parent.Children = append(parent.Children, node)
+	}
+	t.Rows = append(t.Rows, cells)
+	t.treeNodes = append(t.treeNodes, node)
+	t.measureRow(cells)
+	t.measureTreeRow(node)
+	t.dirty = true
+	return node
+}
+
+// TreeMode returns whether the table shows a tree
+func (t *Table) TreeMode() bool {
+	return t.treeNodes != nil
+}
+
+// GetSelectedNode returns the selected node in tree mode
+func (t *Table) GetSelectedNode() *TableNode {
+	if !t.TreeMode() || t.SelectedRow < 0 || t.SelectedRow >= t.rowCount() {
+		return nil
+	}
+	return t.treeNodes[t.rowIndex(t.SelectedRow)]
+}
+
+// SetNodeExpanded expands or collapses a node, keeping the selection on
+// the same row
+func (t *Table) SetNodeExpanded(node *TableNode, expanded bool) {
+	if node == nil || node.Expanded == expanded {
+		return
+	}
+	node.Expanded = expanded
+	t.refreshTree()
+}
+
+// ExpandAll expands every node
+func (t *Table) ExpandAll() {
+	for _, node := range t.treeNodes {
+		node.Expanded = true
+	}
+	t.refreshTree()
+}
+
+// CollapseAll collapses every node
+func (t *Table) CollapseAll() {
+	for _, node := range t.treeNodes {
+		node.Expanded = false
+	}
+	t.refreshTree()
+}
+
+// refreshTree rebuilds the visible rows after nodes were expanded or
+// collapsed. If the selected row was hidden, its closest visible ancestor
+// is selected.
+func (t *Table) refreshTree() {
+	selected := t.GetSelectedNode()
+	t.refreshView()
+	for node := selected; node != nil; node = node.Parent {
+		if t.selectNode(node) {
+			break
+		}
+	}
+	t.captureAnchor()
+}
+
+// selectNode selects the row showing node. It returns false if the node
+// isn't visible.
+func (t *Table) selectNode(node *TableNode) bool {
+	for i := 0; i < t.rowCount(); i++ {
+		if t.rowIndex(i) == node.index {
+			t.SelectedRow = i
+			t.scrollToSelected()
+			return true
+		}
+	}
+	return false
+}
+
+// expandedKeys returns the row keys of the expanded nodes
+func (t *Table) expandedKeys() map[string]bool {
+	if t.RowKey == nil {
+		return nil
+	}
+	keys := make(map[string]bool)
+	for _, node := range t.treeNodes {
+		if node.Expanded {
+			keys[t.RowKey(node.Cells)] = true
+		}
+	}
+	return keys
+}
+
+// sortTree orders the siblings of every node by the sort keys, comparing
+// the values sortValues parsed for each node's row
+func (t *Table) sortTree(nodes []*TableNode, keys []SortKey, values [][]sortValue) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return t.lessValues(keys, values[nodes[i].index], values[nodes[j].index])
+	})
+	for _, node := range nodes {
+		t.sortTree(node.Children, keys, values)
+	}
+}
+
+// refreshTreeView lists the rows of expanded nodes in tree order along
+// with their guides. While filtering, matching rows and their ancestors
+// are shown whether expanded or not.
+func (t *Table) refreshTreeView() {
+	t.view = make([]int, 0, len(t.treeNodes))
+	t.treePrefix = t.treePrefix[:0]
+
+	var keep map[*TableNode]bool
+	if t.filter != nil {
+		keep = make(map[*TableNode]bool)
+		var match func(node *TableNode) bool
+		match = func(node *TableNode) bool {
+			matched := t.filter.matchRow(node.Cells)
+			for _, child := range node.Children {
+				if match(child) {
+					matched = true
+				}
+			}
+			keep[node] = matched
+			return matched
+		}
+		for _, root := range t.tree {
+			match(root)
+		}
+	}
+
+	var walk func(nodes []*TableNode, guides string, depth int)
+	walk = func(nodes []*TableNode, guides string, depth int) {
+		shown := nodes
+		if keep != nil {
+			shown = make([]*TableNode, 0, len(nodes))
+			for _, node := range nodes {
+				if keep[node] {
+					shown = append(shown, node)
+				}
+			}
+		}
+
+		for i, node := range shown {
+			last := i == len(shown)-1
+			open := len(node.Children) > 0 && (node.Expanded || keep != nil)
+
+			var prefix strings.Builder
+			prefix.WriteString(guides)
+			if depth > 0 {
+				if last {
+					prefix.WriteRune(symbols.BLCorner)
+				} else {
+					prefix.WriteRune(symbols.DivLeft)
+				}
+				prefix.WriteRune(symbols.HLine)
+			}
+			switch {
+			case len(node.Children) == 0 && depth > 0:
+				prefix.WriteRune(symbols.HLine)
+			case len(node.Children) == 0:
+				prefix.WriteRune(' ')
+			case open:
+				prefix.WriteRune('-')
+			default:
+				prefix.WriteRune('+')
+			}
+			prefix.WriteRune(' ')
+
+			t.view = append(t.view, node.index)
+			t.treePrefix = append(t.treePrefix, prefix.String())
+
+			if open {
+				childGuides := ""
+				if depth > 0 {
+					childGuides = guides + "  "
+					if !last {
+						childGuides = guides + string(symbols.VLine) + " "
+					}
+				}
+				walk(node.Children, childGuides, depth+1)
+			}
+		}
+	}
+	walk(t.tree, "", 0)
+}
+
+// treeColumn returns the column drawn with guides: the first visible
+// column in display order
+func (t *Table) treeColumn() int {
+	for _, i := range t.displayOrder() {
+		if !t.Columns[i].Hidden {
+			return i
+		}
+	}
+	return -1
+}
+
+// rowPrefix returns the guides drawn before a cell value
+func (t *Table) rowPrefix(rowIdx, col int) string {
+	if !t.TreeMode() || rowIdx >= len(t.treePrefix) || col != t.treeColumn() {
+		return ""
+	}
+	return t.treePrefix[rowIdx]
+}
+
+// measureTreeRow widens the tree column to fit a node's guides and value
+func (t *Table) measureTreeRow(node *TableNode) {
+	col := t.treeColumn()
+	if col < 0 || col >= len(t.contentWidths) {
+		return
+	}
+	if w := 2*node.depth + 2 + textWidth(cellValue(node.Cells, col)); w > t.contentWidths[col] {
+		t.contentWidths[col] = w
+	}
+}
+
+// handleTreeKey handles the tree-table key bindings: Right expands the
+// selected node or moves to its first child, and Left collapses it or
+// moves to its parent. Plain Left and Right are always consumed, even on
+// a leaf or a collapsed root, so columns scroll only with Shift held.
+func (t *Table) handleTreeKey(ev *tcell.EventKey) bool {
+	node := t.GetSelectedNode()
+	if node == nil || ev.Modifiers()&tcell.ModShift != 0 {
+		return false
+	}
+	switch ev.Key() {
+	case tcell.KeyRight:
+		if len(node.Children) == 0 {
+			return true
+		}
+		if !node.Expanded && !t.Filtered() {
+			t.SetNodeExpanded(node, true)
+			return true
+		}
+		t.handleDownKey()
+		return true
+	case tcell.KeyLeft:
+		if node.Expanded && !t.Filtered() {
+			t.SetNodeExpanded(node, false)
+			return true
+		}
+		if node.Parent != nil {
+			t.selectNode(node.Parent)
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package widgets
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func newProcessTree(t *testing.T) (*Table, tcell.SimulationScreen) {
+	t.Helper()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(40, 12)
+
+	table := NewTable(screen, 0, 0, 30, 10)
+	table.SetShowBorder(false)
+	table.SortColumn = -1
+	table.SetColumns([]Column{{Title: "Command"}, {Title: "PID", Type: ColumnInt}})
+	table.SetTree(
+		&TableNode{Cells: []string{"init", "1"}, Expanded: true, Children: []*TableNode{
+			{Cells: []string{"sshd", "300"}, Expanded: true, Children: []*TableNode{
+				{Cells: []string{"bash", "310"}},
+			}},
+			{Cells: []string{"nginx", "200"}, Children: []*TableNode{
+				{Cells: []string{"worker", "201"}},
+			}},
+		}},
+	)
+	return table, screen
+}
+
+func visibleCells(table *Table, col int) []string {
+	var cells []string
+	for i := 0; i < table.rowCount(); i++ {
+		cells = append(cells, table.displayRow(i)[col])
+	}
+	return cells
+}
+
+func TestTableTree(t *testing.T) {
+	t.Parallel()
+	table, screen := newProcessTree(t)
+
+	if got := visibleCells(table, 0); !equalStrings(got, []string{"init", "sshd", "bash", "nginx"}) {
+		t.Errorf("Expected collapsed nginx children to be hidden, got %v", got)
+	}
+
+	table.Draw()
+	want := []string{"- init", "├─- sshd", "│ └── bash", "└─+ nginx"}
+	for i, line := range want {
+		if got := readRow(screen, 0, i+1, len([]rune(line))); got != line {
+			t.Errorf("Row %d: expected %q, got %q", i, line, got)
+		}
+	}
+
+	// Sorting by PID orders siblings only
+	table.SetSortColumn(1)
+	if got := visibleCells(table, 0); !equalStrings(got, []string{"init", "nginx", "sshd", "bash"}) {
+		t.Errorf("Expected siblings sorted by PID, got %v", got)
+	}
+
+	// Right expands nginx, Left on a child moves to its parent
+	table.SelectedRow = 1
+	right := tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone)
+	left := tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone)
+	table.HandleEvent(right)
+	if got := visibleCells(table, 0); len(got) != 5 || got[2] != "worker" {
+		t.Errorf("Expected nginx expanded, got %v", got)
+	}
+	table.HandleEvent(right)
+	if node := table.GetSelectedNode(); node == nil || node.Cells[0] != "worker" {
+		t.Errorf("Expected Right to move to the first child, got %v", node)
+	}
+	table.HandleEvent(left)
+	table.HandleEvent(left)
+	if node := table.GetSelectedNode(); node.Cells[0] != "nginx" || node.Expanded {
+		t.Errorf("Expected nginx selected and collapsed, got %v", node.Cells)
+	}
+
+	// Collapsing a parent of the selected row selects the parent
+	table.SelectedRow = 3 // bash
+	table.CollapseAll()
+	if node := table.GetSelectedNode(); node.Cells[0] != "init" || table.rowCount() != 1 {
+		t.Errorf("Expected init selected after collapsing all, got %v", node.Cells)
+	}
+}
+
+func TestTableTreeHorizontalScroll(t *testing.T) {
+	t.Parallel()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(40, 10)
+
+	table := NewTable(screen, 0, 0, 14, 6)
+	table.SetShowBorder(false)
+	table.SortColumn = -1
+	table.SetColumns([]Column{
+		{Title: "AAAA", MinWidth: 4},
+		{Title: "BBBB", MinWidth: 4},
+		{Title: "CCCC", MinWidth: 4},
+		{Title: "DDDD", MinWidth: 4},
+	})
+	table.SetTree(&TableNode{Cells: []string{"root", "b", "c", "d"}, Children: []*TableNode{
+		{Cells: []string{"leaf", "b", "c", "d"}},
+	}})
+
+	// Left on a collapsed root and Right on a leaf keep the columns still
+	left := tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone)
+	right := tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone)
+	if !table.HandleEvent(left) || table.ColumnOffset != 0 {
+		t.Errorf("Expected Left on the root to be consumed without scrolling, offset %d", table.ColumnOffset)
+	}
+	table.HandleEvent(right)
+	table.HandleEvent(right)
+	if node := table.GetSelectedNode(); node == nil || node.Cells[0] != "leaf" {
+		t.Fatalf("Expected the leaf selected, got %v", node)
+	}
+	if !table.HandleEvent(right) || table.ColumnOffset != 0 {
+		t.Errorf("Expected Right on a leaf to be consumed without scrolling, offset %d", table.ColumnOffset)
+	}
+
+	// Shift+Right scrolls the columns instead of moving one
+	if !table.HandleEvent(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModShift)) {
+		t.Fatal("Shift+Right should scroll the columns")
+	}
+	if table.ColumnOffset != 1 || table.CurrentColumn >= 0 {
+		t.Errorf("Expected offset 1 without a current column, got %d and %d", table.ColumnOffset, table.CurrentColumn)
+	}
+	table.HandleEvent(tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModShift))
+	if table.ColumnOffset != 0 {
+		t.Errorf("Expected Shift+Left to scroll back, got %d", table.ColumnOffset)
+	}
+}
+
+func TestTableTreeFilter(t *testing.T) {
+	t.Parallel()
+	table, screen := newProcessTree(t)
+
+	if err := table.SetFilter("worker"); err != nil {
+		t.Fatal(err)
+	}
+	if got := visibleCells(table, 0); !equalStrings(got, []string{"init", "nginx", "worker"}) {
+		t.Errorf("Expected the match with its ancestors, got %v", got)
+	}
+
+	table.Draw()
+	if got := readRow(screen, 0, 3, 10); !strings.HasPrefix(got, "  └── work") {
+		t.Errorf("Expected guides before the match, got %q", got)
+	}
+	_, _, style, _ := screen.GetContent(6, 3)
+	if style != table.MatchStyle {
+		t.Error("Expected the match highlighted after the guides")
+	}
+}
+
+func TestTableTreeRowKey(t *testing.T) {
+	t.Parallel()
+	table, _ := newProcessTree(t)
+	table.SetRowKey(KeyColumn(1))
+	table.ExpandAll()
+
+	// A refreshed tree keeps the expanded state of known rows
+	table.SetTree(&TableNode{Cells: []string{"init", "1"}, Children: []*TableNode{
+		{Cells: []string{"nginx", "200"}, Children: []*TableNode{{Cells: []string{"worker", "201"}}}},
+	}})
+	if got := visibleCells(table, 0); !equalStrings(got, []string{"init", "nginx", "worker"}) {
+		t.Errorf("Expected expanded state to carry over, got %v", got)
+	}
+
+	node := table.AddNode(table.tree[0], []string{"cron", "400"})
+	if got := visibleCells(table, 0); len(got) != 4 || got[3] != "cron" || node.Parent.Cells[0] != "init" {
+		t.Errorf("Expected the added node below init, got %v", got)
+	}
+}
@@ -0,0 +1,149 @@
+package widgets
+
+import (
+	"github.com/deadjoe/termdodo/symbols"
+	"github.com/gdamore/tcell/v2"
+)
+
+// CheckState is the state of a node's checkbox
+type CheckState int
+
+// Check states
+const (
+	// Unchecked nodes have no checked descendants
+	Unchecked CheckState = iota
+	// Checked nodes have all their descendants checked
+	Checked
+	// PartiallyChecked nodes have some, but not all, descendants checked
+	PartiallyChecked
+)
+
+// checkboxWidth is the width of a checkbox and the space after it
+const checkboxWidth = 4
+
+// SetCheckable sets whether nodes are drawn with checkboxes that Space
+// toggles
+func (t *TreeView) SetCheckable(checkable bool) {
+	t.Checkable = checkable
+}
+
+// CheckState returns the state of a node's checkbox. A node with children
+// is checked if all its children are, and partially checked if only some
+// of them are.
+func (t *TreeView) CheckState(node *TreeNode) CheckState {
+	if node == nil {
+		return Unchecked
+	}
+	if len(node.Children) == 0 {
+		if node.Checked {
+			return Checked
+		}
+		return Unchecked
+	}
+
+	checked, unchecked := 0, 0
+	for _, child := range node.Children {
+		switch t.CheckState(child) {
+		case Checked:
+			checked++
+		case Unchecked:
+			unchecked++
+		default:
+			return PartiallyChecked
+		}
+	}
+	switch {
+	case unchecked == 0:
+		return Checked
+	case checked == 0:
+		return Unchecked
+	}
+	return PartiallyChecked
+}
+
+// SetChecked checks or unchecks a node along with all its descendants and
+// calls OnCheck
+func (t *TreeView) SetChecked(node *TreeNode, checked bool) {
+	if node == nil {
+		return
+	}
+	var walk func(n *TreeNode)
+	walk = func(n *TreeNode) {
+		n.Checked = checked
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(node)
+
+	// Keep the ancestors' flags in step, so children loaded later inherit
+	// them
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		parent.Checked = t.CheckState(parent) == Checked
+	}
+	if t.OnCheck != nil {
+		t.OnCheck(node, t.CheckState(node))
+	}
+}
+
+// ToggleChecked checks a node unless it is fully checked, in which case it
+// is unchecked
+func (t *TreeView) ToggleChecked(node *TreeNode) {
+	if node != nil {
+		t.SetChecked(node, t.CheckState(node) != Checked)
+	}
+}
+
+// CheckedNodes returns the checked nodes in tree order, including parents
+// whose descendants are all checked
+func (t *TreeView) CheckedNodes() []*TreeNode {
+	var nodes []*TreeNode
+	t.walkChecked(t.Root, func(node *TreeNode) {
+		nodes = append(nodes, node)
+	})
+	return nodes
+}
+
+// CheckedLeaves returns the checked nodes without children in tree order
+func (t *TreeView) CheckedLeaves() []*TreeNode {
+	var nodes []*TreeNode
+	t.walkChecked(t.Root, func(node *TreeNode) {
+		if len(node.Children) == 0 {
+			nodes = append(nodes, node)
+		}
+	})
+	return nodes
+}
+
+// walkChecked calls fn for every checked node below and including node
+func (t *TreeView) walkChecked(node *TreeNode, fn func(node *TreeNode)) {
+	if node == nil {
+		return
+	}
+	if t.CheckState(node) == Checked {
+		fn(node)
+	}
+	for _, child := range node.Children {
+		t.walkChecked(child, fn)
+	}
+}
+
+// drawCheckbox draws a node's checkbox at x and returns the x after it
+func (t *TreeView) drawCheckbox(node *TreeNode, x, y int, style tcell.Style) int {
+	mark := ' '
+	switch t.CheckState(node) {
+	case Checked:
+		mark = symbols.Check
+		if node != t.Selected || !t.FullRowHighlight {
+			style = t.CheckedStyle
+		}
+	case PartiallyChecked:
+		mark = '-'
+	}
+	for i, r := range []rune{'[', mark, ']', ' '} {
+		if x+i < t.X+t.Width {
+			t.Screen.SetContent(x+i, y, r, nil, style)
+		}
+	}
+	return x + checkboxWidth
+}
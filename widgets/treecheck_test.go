@@ -0,0 +1,85 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestTreeViewCheckboxes(t *testing.T) {
+	t.Parallel()
+	screen := createTestScreen(t)
+	screen.SetSize(30, 10)
+	tree := NewTreeView(screen, 0, 0, 30, 10)
+	tree.SetShowLines(false)
+	tree.SetCheckable(true)
+
+	root := tree.AddNode(nil, "services")
+	web := tree.AddNode(root, "web")
+	nginx := tree.AddNode(web, "nginx")
+	php := tree.AddNode(web, "php-fpm")
+	db := tree.AddNode(root, "postgres")
+	tree.ExpandAll()
+
+	var events []string
+	tree.OnCheck = func(node *TreeNode, state CheckState) {
+		events = append(events, node.Text)
+	}
+
+	// Checking a parent checks its descendants
+	tree.SetChecked(web, true)
+	if !nginx.Checked || !php.Checked || tree.CheckState(web) != Checked {
+		t.Error("Expected web and its children checked")
+	}
+	if tree.CheckState(root) != PartiallyChecked {
+		t.Errorf("Expected services partially checked, got %v", tree.CheckState(root))
+	}
+
+	// Unchecking a child makes the parent indeterminate
+	tree.Selected = php
+	tree.HandleKeyEvent(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone))
+	if php.Checked || tree.CheckState(web) != PartiallyChecked {
+		t.Error("Expected Space to uncheck php-fpm")
+	}
+
+	tree.Draw()
+	want := []string{"- [-] services", "    - [-] web", "        [✓] nginx", "        [ ] php-fpm", "    [ ] postgres"}
+	for i, line := range want {
+		if got := readRow(screen, 0, i, len([]rune(line))); got != line {
+			t.Errorf("Row %d: expected %q, got %q", i, line, got)
+		}
+	}
+
+	// Toggling a partially checked node checks everything below it
+	tree.ToggleChecked(root)
+	if tree.CheckState(root) != Checked || !db.Checked || !root.Checked {
+		t.Error("Expected toggling a partial node to check it")
+	}
+	if got := len(tree.CheckedNodes()); got != 5 {
+		t.Errorf("Expected 5 checked nodes, got %d", got)
+	}
+	tree.SetChecked(nginx, false)
+	leaves := tree.CheckedLeaves()
+	if len(leaves) != 2 || leaves[0] != php || leaves[1] != db || root.Checked {
+		t.Errorf("Unexpected checked leaves %v", leaves)
+	}
+
+	if want := []string{"web", "php-fpm", "services", "nginx"}; !equalStrings(events, want) {
+		t.Errorf("Expected OnCheck calls %v, got %v", want, events)
+	}
+}
+
+func TestTreeViewCheckedLazyChildren(t *testing.T) {
+	t.Parallel()
+	tree := NewTreeView(createTestScreen(t), 0, 0, 30, 10)
+	tree.SetCheckable(true)
+	root := tree.AddLazyNode(nil, "root", func(node *TreeNode, done func([]*TreeNode, error)) {
+		done([]*TreeNode{{Text: "a"}, {Text: "b"}}, nil)
+	})
+
+	tree.SetChecked(root, true)
+	tree.ExpandSelected()
+	if len(root.Children) != 2 || !root.Children[0].Checked || tree.CheckState(root) != Checked {
+		t.Error("Expected children loaded into a checked node to be checked")
+	}
+}
@@ -0,0 +1,344 @@
+package widgets
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// fuzzyMatch reports whether the characters of pattern appear in text in
+// order, ignoring case, and returns their rune positions in text. Of the
+// matches ending where the leftmost one does, the shortest is used, so
+// "ngx" highlights "nginx" rather than scattered letters before it.
+func fuzzyMatch(pattern, text string) ([]int, bool) {
+	p := []rune(pattern)
+	r := []rune(text)
+	if len(p) == 0 {
+		return nil, false
+	}
+
+	// Find the end of the leftmost match, then walk back from it to
+	// tighten the match, like fzf's v1 algorithm
+	pi, end := 0, -1
+	for i := 0; i < len(r) && pi < len(p); i++ {
+		if unicode.ToLower(r[i]) == unicode.ToLower(p[pi]) {
+			pi++
+			if pi == len(p) {
+				end = i
+			}
+		}
+	}
+	if end < 0 {
+		return nil, false
+	}
+	start := end
+	for pi = len(p) - 1; start >= 0; start-- {
+		if unicode.ToLower(r[start]) == unicode.ToLower(p[pi]) {
+			pi--
+			if pi < 0 {
+				break
+			}
+		}
+	}
+
+	positions := make([]int, 0, len(p))
+	pi = 0
+	for i := start; i <= end && pi < len(p); i++ {
+		if unicode.ToLower(r[i]) == unicode.ToLower(p[pi]) {
+			positions = append(positions, i)
+			pi++
+		}
+	}
+	return positions, true
+}
+
+// SetFilter hides the nodes that neither fuzzy-match query nor have a
+// matching descendant, and highlights the matched characters. Ancestors of
+// matches are shown expanded while the filter is active. Only loaded
+// children are searched. An empty query clears the filter. It returns the
+// number of matches.
+func (t *TreeView) SetFilter(query string) int {
+	t.Filter = query
+	if !t.match() {
+		return 0
+	}
+	if len(t.matches) > 0 && t.positions[t.Selected] == nil {
+		t.Selected = t.matches[0]
+	}
+	if !t.keep[t.Selected] {
+		t.Selected = t.Root
+	}
+	t.scrollToSelected()
+	return len(t.matches)
+}
+
+// refilter reapplies the filter after nodes were added, removed or
+// loaded. The selection only moves if the filter now hides it.
+func (t *TreeView) refilter() {
+	if t.Filter == "" || !t.match() {
+		return
+	}
+	if t.Selected != nil && !t.keep[t.Selected] {
+		t.Selected = t.Root
+		if len(t.matches) > 0 {
+			t.Selected = t.matches[0]
+		}
+	}
+}
+
+// match finds the nodes matching Filter and their ancestors. It returns
+// false if there is no filter.
+func (t *TreeView) match() bool {
+	query := t.Filter
+	t.matches = nil
+	t.positions = nil
+	t.keep = nil
+	if query == "" || t.Root == nil {
+		return false
+	}
+
+	t.positions = make(map[*TreeNode][]int)
+	t.keep = make(map[*TreeNode]bool)
+	var walk func(node *TreeNode) bool
+	walk = func(node *TreeNode) bool {
+		kept := false
+		if positions, ok := fuzzyMatch(query, node.Text); ok {
+			t.positions[node] = positions
+			t.matches = append(t.matches, node)
+			kept = true
+		}
+		for _, child := range node.Children {
+			if walk(child) {
+				kept = true
+			}
+		}
+		if kept {
+			t.keep[node] = true
+		}
+		return kept
+	}
+	walk(t.Root)
+	return true
+}
+
+// ClearFilter shows all nodes again
+func (t *TreeView) ClearFilter() {
+	t.SetFilter("")
+	t.EnsureVisible()
+}
+
+// Prompting returns whether the filter prompt is open and taking keys
+func (t *TreeView) Prompting() bool {
+	return t.prompt
+}
+
+// Filtered returns whether a filter is active
+func (t *TreeView) Filtered() bool {
+	return t.keep != nil
+}
+
+// Matches returns the nodes matching the filter in tree order
+func (t *TreeView) Matches() []*TreeNode {
+	return t.matches
+}
+
+// NextMatch selects the next node matching the filter, wrapping around,
+// and expands its collapsed ancestors
+func (t *TreeView) NextMatch() bool {
+	return t.jumpToMatch(1)
+}
+
+// PreviousMatch selects the previous node matching the filter
+func (t *TreeView) PreviousMatch() bool {
+	return t.jumpToMatch(-1)
+}
+
+// jumpToMatch selects the match after or before the selected node
+func (t *TreeView) jumpToMatch(direction int) bool {
+	if len(t.matches) == 0 {
+		return false
+	}
+	// Matches are in tree order, so find the selection's place among them
+	order := make(map[*TreeNode]int)
+	var walk func(node *TreeNode)
+	walk = func(node *TreeNode) {
+		order[node] = len(order)
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(t.Root)
+
+	pos := order[t.Selected]
+	idx := -1
+	if direction > 0 {
+		for i, node := range t.matches {
+			if order[node] > pos {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			idx = 0
+		}
+	} else {
+		for i := len(t.matches) - 1; i >= 0; i-- {
+			if order[t.matches[i]] < pos {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			idx = len(t.matches) - 1
+		}
+	}
+
+	t.Selected = t.matches[idx]
+	t.EnsureVisible() // expands the ancestors
+	return true
+}
+
+// step selects the node delta lines below the selected one
+func (t *TreeView) step(delta int) bool {
+	nodes := t.visibleNodes()
+	for i, node := range nodes {
+		if node == t.Selected {
+			if j := i + delta; j >= 0 && j < len(nodes) {
+				t.Selected = nodes[j]
+				t.scrollToSelected()
+				return true
+			}
+			return false
+		}
+	}
+	return false
+}
+
+// scrollToSelected scrolls the least amount needed to show the selected
+// node
+func (t *TreeView) scrollToSelected() {
+	pos := -1
+	for i, node := range t.visibleNodes() {
+		if node == t.Selected {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
+		return
+	}
+	if pos < t.ScrollOffset {
+		t.ScrollOffset = pos
+	} else if pos >= t.ScrollOffset+t.rows() {
+		t.ScrollOffset = pos - t.rows() + 1
+	}
+	if t.ScrollOffset < 0 {
+		t.ScrollOffset = 0
+	}
+}
+
+// isOpen returns whether a node's children are shown
+func (t *TreeView) isOpen(node *TreeNode) bool {
+	if t.keep != nil {
+		return t.keep[node] && len(t.shownChildren(node)) > 0
+	}
+	return node.Expanded
+}
+
+// shownChildren returns the children that pass the filter
+func (t *TreeView) shownChildren(node *TreeNode) []*TreeNode {
+	if t.keep == nil {
+		return node.Children
+	}
+	shown := make([]*TreeNode, 0, len(node.Children))
+	for _, child := range node.Children {
+		if t.keep[child] {
+			shown = append(shown, child)
+		}
+	}
+	return shown
+}
+
+// rows returns the number of lines available for nodes
+func (t *TreeView) rows() int {
+	if t.prompt {
+		return t.Height - 1
+	}
+	return t.Height
+}
+
+// handlePromptKey handles keys while the filter prompt is open. The
+// filter is applied as the query is typed; Enter keeps it and Esc clears
+// it.
+func (t *TreeView) handlePromptKey(ev *tcell.EventKey) bool {
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		t.prompt = false
+		t.ClearFilter()
+		return true
+	case tcell.KeyEnter:
+		t.prompt = false
+		return true
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		runes := []rune(t.promptText)
+		if len(runes) == 0 {
+			return true
+		}
+		t.promptText = string(runes[:len(runes)-1])
+	case tcell.KeyRune:
+		t.promptText += string(ev.Rune())
+	default:
+		return true
+	}
+	t.SetFilter(t.promptText)
+	return true
+}
+
+// handleFilterKey handles the filter key bindings: / opens the prompt, n
+// and N cycle through matches and Esc clears the filter
+func (t *TreeView) handleFilterKey(ev *tcell.EventKey) bool {
+	switch ev.Key() {
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case '/':
+			t.prompt = true
+			t.promptText = t.Filter
+			return true
+		case 'n':
+			if t.Filtered() {
+				return t.NextMatch()
+			}
+		case 'N':
+			if t.Filtered() {
+				return t.PreviousMatch()
+			}
+		}
+	case tcell.KeyEscape:
+		if t.Filtered() {
+			t.ClearFilter()
+			return true
+		}
+	}
+	return false
+}
+
+// drawPrompt draws the query being typed on the last line
+func (t *TreeView) drawPrompt() {
+	y := t.Y + t.Height - 1
+	text := []rune("/" + t.promptText)
+	status := []rune(fmt.Sprintf(" %d matches", len(t.matches)))
+	for i := 0; i < t.Width; i++ {
+		r := ' '
+		style := t.Style
+		switch {
+		case i < len(text):
+			r = text[i]
+		case i == len(text):
+			style = style.Reverse(true) // cursor
+		case i >= t.Width-len(status):
+			r = status[i-(t.Width-len(status))]
+		}
+		t.Screen.SetContent(t.X+i, y, r, nil, style)
+	}
+}
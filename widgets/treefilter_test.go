@@ -0,0 +1,180 @@
+package widgets
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		pattern, text string
+		want          []int
+		ok            bool
+	}{
+		{"ngx", "nginx.conf", []int{0, 1, 4}, true},
+		{"NGX", "nginx", []int{0, 1, 4}, true},
+		{"conf", "nginx.conf", []int{6, 7, 8, 9}, true},
+		{"cf", "c/config", []int{2, 5}, true},
+		{"xn", "nginx", nil, false},
+		{"", "nginx", nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := fuzzyMatch(tt.pattern, tt.text)
+		if ok != tt.ok || fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, %v; want %v, %v", tt.pattern, tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func newFilterTree(t *testing.T) (*TreeView, tcell.SimulationScreen) {
+	t.Helper()
+	screen := createTestScreen(t).(tcell.SimulationScreen)
+	screen.SetSize(30, 10)
+	tree := NewTreeView(screen, 0, 0, 30, 10)
+	tree.SetShowLines(false)
+
+	root := tree.AddNode(nil, "etc")
+	nginx := tree.AddNode(root, "nginx")
+	tree.AddNode(nginx, "nginx.conf")
+	tree.AddNode(nginx, "mime.types")
+	ssh := tree.AddNode(root, "ssh")
+	tree.AddNode(ssh, "sshd_config")
+	tree.AddNode(root, "hosts")
+	root.Expanded = true
+	return tree, screen
+}
+
+func visibleTexts(tree *TreeView) []string {
+	var texts []string
+	for _, node := range tree.visibleNodes() {
+		texts = append(texts, node.Text)
+	}
+	return texts
+}
+
+func TestTreeViewFilter(t *testing.T) {
+	t.Parallel()
+	tree, screen := newFilterTree(t)
+
+	if n := tree.SetFilter("conf"); n != 2 {
+		t.Errorf("Expected 2 matches, got %d", n)
+	}
+	// Collapsed ancestors of matches are shown, other branches hidden
+	if got := visibleTexts(tree); !equalStrings(got, []string{"etc", "nginx", "nginx.conf", "ssh", "sshd_config"}) {
+		t.Errorf("Unexpected nodes %v", got)
+	}
+	if tree.Selected.Text != "nginx.conf" {
+		t.Errorf("Expected the first match selected, got %q", tree.Selected.Text)
+	}
+
+	tree.Draw()
+	if got := readRow(screen, 0, 2, 18); got != "        nginx.conf" {
+		t.Errorf("Unexpected row %q", got)
+	}
+	for x := 14; x < 18; x++ {
+		if _, _, style, _ := screen.GetContent(x, 2); style != tree.MatchStyle {
+			t.Errorf("Expected the match highlighted at %d", x)
+		}
+	}
+	if _, _, style, _ := screen.GetContent(8, 2); style == tree.MatchStyle {
+		t.Error("Expected unmatched characters not highlighted")
+	}
+
+	// Navigation only visits the nodes shown
+	tree.SelectNext()
+	if tree.Selected.Text != "ssh" {
+		t.Errorf("Expected Down to skip hidden nodes, got %q", tree.Selected.Text)
+	}
+
+	// n and N cycle through the matches and expand their parents
+	key := func(r rune) {
+		tree.HandleKeyEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	key('n')
+	if tree.Selected.Text != "sshd_config" || !tree.Selected.Parent.Expanded {
+		t.Errorf("Expected n to select sshd_config and expand ssh, got %q", tree.Selected.Text)
+	}
+	key('n')
+	if tree.Selected.Text != "nginx.conf" {
+		t.Errorf("Expected n to wrap around, got %q", tree.Selected.Text)
+	}
+	key('N')
+	if tree.Selected.Text != "sshd_config" {
+		t.Errorf("Expected N to go back, got %q", tree.Selected.Text)
+	}
+
+	tree.HandleKeyEvent(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	if tree.Filtered() || len(visibleTexts(tree)) != 7 {
+		t.Errorf("Expected Esc to clear the filter, got %v", visibleTexts(tree))
+	}
+	if tree.Selected.Text != "sshd_config" {
+		t.Error("Expected the selection kept after clearing the filter")
+	}
+}
+
+func TestTreeViewFilterPrompt(t *testing.T) {
+	t.Parallel()
+	tree, screen := newFilterTree(t)
+	key := func(k tcell.Key, r rune) {
+		tree.HandleKeyEvent(tcell.NewEventKey(k, r, tcell.ModNone))
+	}
+
+	key(tcell.KeyRune, '/')
+	for _, r := range "hst" {
+		key(tcell.KeyRune, r)
+	}
+	if tree.Filter != "hst" || len(tree.Matches()) != 1 || tree.Selected.Text != "hosts" {
+		t.Errorf("Expected the filter applied while typing, got %q", tree.Filter)
+	}
+	tree.Draw()
+	if got := readRow(screen, 0, 9, 4); got != "/hst" {
+		t.Errorf("Expected the prompt on the last line, got %q", got)
+	}
+
+	key(tcell.KeyBackspace2, 0)
+	if tree.Filter != "hs" {
+		t.Errorf("Expected backspace to edit the query, got %q", tree.Filter)
+	}
+	key(tcell.KeyEnter, 0)
+	if tree.prompt || !tree.Filtered() {
+		t.Error("Expected Enter to close the prompt and keep the filter")
+	}
+}
+
+func TestTreeViewFilterFollowsChanges(t *testing.T) {
+	t.Parallel()
+	tree, _ := newFilterTree(t)
+	tree.SetFilter("conf")
+	ssh := tree.FindNode("ssh")
+
+	// Removed nodes stop matching, so n never selects a detached node
+	tree.Selected = tree.FindNode("nginx.conf")
+	tree.RemoveNode(ssh)
+	if len(tree.Matches()) != 1 {
+		t.Errorf("Expected 1 match after removing ssh, got %d", len(tree.Matches()))
+	}
+	tree.NextMatch()
+	if tree.Selected.Parent == nil {
+		t.Errorf("Expected an attached node selected, got %q", tree.Selected.Text)
+	}
+
+	// Added nodes are matched without moving the selection
+	tree.AddNode(tree.Root, "resolv.conf")
+	if got := visibleTexts(tree); !equalStrings(got, []string{"etc", "nginx", "nginx.conf", "resolv.conf"}) {
+		t.Errorf("Unexpected nodes %v", got)
+	}
+	if tree.Selected.Text != "nginx.conf" {
+		t.Errorf("Expected the selection kept, got %q", tree.Selected.Text)
+	}
+
+	// A new root is filtered too
+	root := &TreeNode{Text: "usr"}
+	root.Children = []*TreeNode{{Text: "share", Parent: root}, {Text: "lib.conf", Parent: root}}
+	tree.SetRoot(root)
+	if got := visibleTexts(tree); !equalStrings(got, []string{"usr", "lib.conf"}) {
+		t.Errorf("Expected the new root filtered, got %v", got)
+	}
+}
@@ -0,0 +1,231 @@
+package widgets
+
+import (
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// NodeLoader loads the children of a node the first time it is expanded.
+// It must call done exactly once, either before returning or later from
+// another goroutine; the result is applied on the next Draw or key event,
+// so loaders never modify the tree while it is drawn.
+type NodeLoader func(node *TreeNode, done func(children []*TreeNode, err error))
+
+// loadState tracks where a lazily loaded node is in loading its children
+type loadState int
+
+const (
+	loadNone loadState = iota
+	loadRunning
+	loadDone
+	loadFailed
+)
+
+// loadResult is a finished load waiting to be applied
+type loadResult struct {
+	node     *TreeNode
+	gen      int
+	children []*TreeNode
+	err      error
+}
+
+// loadQueue hands finished loads from loader goroutines to the draw loop
+type loadQueue struct {
+	mu      sync.Mutex
+	results []loadResult
+}
+
+// push queues a finished load
+func (q *loadQueue) push(result loadResult) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.results = append(q.results, result)
+}
+
+// take returns and clears the queued loads of node, or of all nodes if
+// node is nil
+func (q *loadQueue) take(node *TreeNode) []loadResult {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if node == nil {
+		results := q.results
+		q.results = nil
+		return results
+	}
+	var taken, kept []loadResult
+	for _, result := range q.results {
+		if result.node == node {
+			taken = append(taken, result)
+		} else {
+			kept = append(kept, result)
+		}
+	}
+	q.results = kept
+	return taken
+}
+
+// Loading returns whether the node's loader is running
+func (n *TreeNode) Loading() bool {
+	return n.load == loadRunning
+}
+
+// LoadError returns the error the node's loader failed with, or nil
+func (n *TreeNode) LoadError() error {
+	return n.loadErr
+}
+
+// hasChildren returns whether the node has or may load children
+func (n *TreeNode) hasChildren() bool {
+	return len(n.Children) > 0 || (n.Loader != nil && n.load != loadDone)
+}
+
+// AddLazyNode adds a node below parent whose children are loaded by loader
+// when it is first expanded
+func (t *TreeView) AddLazyNode(parent *TreeNode, text string, loader NodeLoader) *TreeNode {
+	node := t.AddNode(parent, text)
+	if node != nil {
+		node.Loader = loader
+	}
+	return node
+}
+
+// LoadChildren runs the node's loader unless it has run already or is
+// running. Expanding a node with a loader calls it, as do key events for
+// expanded nodes that haven't loaded; drawing never starts a load.
+func (t *TreeView) LoadChildren(node *TreeNode) {
+	if node == nil || node.Loader == nil || node.load == loadRunning || node.load == loadDone {
+		return
+	}
+	node.load = loadRunning
+	node.loadErr = nil
+	node.loadGen++
+
+	gen := node.loadGen
+	var once sync.Once
+	node.Loader(node, func(children []*TreeNode, err error) {
+		once.Do(func() {
+			t.loads.push(loadResult{node: node, gen: gen, children: children, err: err})
+			if t.Screen != nil {
+				_ = t.Screen.PostEvent(tcell.NewEventInterrupt(node))
+			}
+		})
+	})
+	// Loaders that finished synchronously show their children right away.
+	// Other nodes' results wait for ApplyLoaded.
+	t.applyLoaded(node)
+}
+
+// ReloadNode discards the node's children and runs its loader again
+func (t *TreeView) ReloadNode(node *TreeNode) {
+	if node == nil || node.Loader == nil {
+		return
+	}
+	for sel := t.Selected; sel != nil; sel = sel.Parent {
+		if sel.Parent == node {
+			t.Selected = node
+			break
+		}
+	}
+	node.Children = nil
+	node.load = loadNone
+	node.loadGen++ // ignore a load still running
+	if node.Expanded {
+		t.LoadChildren(node)
+	}
+}
+
+// ApplyLoaded adds the children of finished loads to the tree. Draw and
+// HandleKeyEvent call it; it returns whether anything changed.
+func (t *TreeView) ApplyLoaded() bool {
+	return t.applyLoaded(nil)
+}
+
+// applyLoaded applies the finished loads of only, or of all nodes if only
+// is nil
+func (t *TreeView) applyLoaded(only *TreeNode) bool {
+	changed := false
+	for _, result := range t.loads.take(only) {
+		node := result.node
+		if result.gen != node.loadGen || node.load != loadRunning {
+			continue
+		}
+		changed = true
+		if result.err != nil {
+			node.load = loadFailed
+			node.loadErr = result.err
+			continue
+		}
+		node.load = loadDone
+		node.Children = result.children
+		for _, child := range node.Children {
+			child.Parent = node
+			if node.Checked {
+				child.Checked = true
+			}
+			if child.Style == (tcell.Style{}) {
+				child.Style = t.Style
+			}
+		}
+	}
+	if changed {
+		t.refilter()
+	}
+	return changed
+}
+
+// loadExpanded starts loading the visible expanded nodes that haven't
+// loaded, e.g. ones expanded by setting Expanded directly
+func (t *TreeView) loadExpanded() {
+	for _, node := range t.visibleNodes() {
+		if node.Expanded && node.load == loadNone {
+			t.LoadChildren(node)
+		}
+	}
+}
+
+// setExpanded expands or collapses a node, loading its children on first
+// expand. Collapsing a node whose loader failed lets expanding retry.
+func (t *TreeView) setExpanded(node *TreeNode, expanded bool) {
+	node.Expanded = expanded
+	if expanded {
+		t.LoadChildren(node)
+	} else if node.load == loadFailed {
+		node.load = loadNone
+		node.loadErr = nil
+	}
+}
+
+// drawLoadStatus draws the placeholder below an expanded node whose
+// children are loading or failed to load, after the given guides. It
+// returns the next line.
+func (t *TreeView) drawLoadStatus(node *TreeNode, guides string, y int) int {
+	var text string
+	style := t.LoadingStyle
+	switch node.load {
+	case loadRunning:
+		text = "loading…"
+	case loadFailed:
+		text = "error: " + node.loadErr.Error()
+		style = t.ErrorStyle
+	default:
+		return y
+	}
+	if y >= t.Y && y < t.Y+t.rows() {
+		x := t.X
+		for _, r := range guides {
+			if x < t.X+t.Width {
+				t.Screen.SetContent(x, y, r, nil, t.LineStyle)
+			}
+			x++
+		}
+		for _, r := range text {
+			if x >= t.X+t.Width {
+				break
+			}
+			t.Screen.SetContent(x, y, r, nil, style)
+			x++
+		}
+	}
+	return y + 1
+}
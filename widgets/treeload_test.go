@@ -0,0 +1,154 @@
+package widgets
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestTreeViewLazyLoad(t *testing.T) {
+	t.Parallel()
+	screen := createTestScreen(t)
+	screen.SetSize(30, 10)
+	tree := NewTreeView(screen, 0, 0, 30, 10)
+	tree.SetShowLines(false)
+
+	calls := 0
+	root := tree.AddLazyNode(nil, "root", func(node *TreeNode, done func([]*TreeNode, error)) {
+		calls++
+		done([]*TreeNode{{Text: "a"}, {Text: "b"}}, nil)
+	})
+	tree.Draw()
+	if got := readRow(screen, 0, 0, 6); got != "+ root" {
+		t.Errorf("Expected an unloaded node to be expandable, got %q", got)
+	}
+
+	tree.HandleKeyEvent(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone))
+	if calls != 1 || len(root.Children) != 2 || root.Children[0].Parent != root {
+		t.Fatalf("Expected children loaded on expand, got %d calls and %d children", calls, len(root.Children))
+	}
+
+	// The loader only runs once
+	tree.CollapseSelected()
+	tree.ExpandSelected()
+	tree.Draw()
+	if calls != 1 {
+		t.Errorf("Expected one load, got %d", calls)
+	}
+	if got := readRow(screen, 0, 1, 5); got != "    a" {
+		t.Errorf("Expected the children drawn, got %q", got)
+	}
+}
+
+func TestTreeViewAsyncLoad(t *testing.T) {
+	t.Parallel()
+	screen := createTestScreen(t).(tcell.SimulationScreen)
+	screen.SetSize(30, 10)
+	tree := NewTreeView(screen, 0, 0, 30, 10)
+	tree.SetShowLines(false)
+
+	results := make(chan error)
+	finished := make(chan struct{})
+	root := tree.AddLazyNode(nil, "root", func(node *TreeNode, done func([]*TreeNode, error)) {
+		go func() {
+			err := <-results
+			done([]*TreeNode{{Text: "child"}}, err)
+			finished <- struct{}{}
+		}()
+	})
+
+	// Drawing doesn't start loads, so the tree never changes while drawn
+	root.Expanded = true
+	tree.Draw()
+	if root.Loading() {
+		t.Fatal("Expected drawing not to start loading")
+	}
+	tree.HandleKeyEvent(tcell.NewEventKey(tcell.KeyCtrlL, 0, tcell.ModNone))
+	if !root.Loading() {
+		t.Fatal("Expected a key event to load the expanded node")
+	}
+	tree.Draw()
+	if got := readRow(screen, 0, 1, 12); got != "    loading…" {
+		t.Errorf("Expected a placeholder, got %q", got)
+	}
+
+	// A failed load is shown inline and retried on the next expand
+	results <- errors.New("timeout")
+	<-finished
+	if ev := screen.PollEvent(); ev == nil {
+		t.Error("Expected an event waking up the draw loop")
+	}
+	tree.Draw()
+	if root.LoadError() == nil || len(root.Children) != 0 {
+		t.Error("Expected the load to fail")
+	}
+	if got := readRow(screen, 0, 1, 18); !strings.HasPrefix(got, "    error: timeout") {
+		t.Errorf("Expected the error shown, got %q", got)
+	}
+
+	tree.CollapseSelected()
+	tree.ExpandSelected()
+	results <- nil
+	<-finished
+	tree.Draw()
+	if root.Loading() || root.LoadError() != nil || len(root.Children) != 1 {
+		t.Errorf("Expected the retry to load the children, got %d", len(root.Children))
+	}
+
+}
+
+func TestTreeViewReloadDropsStaleResults(t *testing.T) {
+	t.Parallel()
+	tree := NewTreeView(createTestScreen(t), 0, 0, 30, 10)
+
+	var loads []func([]*TreeNode, error)
+	root := tree.AddLazyNode(nil, "root", func(node *TreeNode, done func([]*TreeNode, error)) {
+		loads = append(loads, done)
+	})
+	tree.ExpandSelected()
+	tree.ReloadNode(root)
+	if len(loads) != 2 || !root.Loading() {
+		t.Fatalf("Expected the reload to start a second load, got %d", len(loads))
+	}
+
+	loads[0](nil, errors.New("stale"))
+	tree.ApplyLoaded()
+	if !root.Loading() || root.LoadError() != nil {
+		t.Error("Expected the superseded load to be dropped")
+	}
+	loads[1]([]*TreeNode{{Text: "child"}}, nil)
+	if !tree.ApplyLoaded() || len(root.Children) != 1 {
+		t.Errorf("Expected the latest load applied, got %d children", len(root.Children))
+	}
+}
+
+func TestTreeViewSyncLoadLeavesOtherResults(t *testing.T) {
+	t.Parallel()
+	tree := NewTreeView(createTestScreen(t), 0, 0, 30, 10)
+
+	root := tree.AddNode(nil, "root")
+	var pending func([]*TreeNode, error)
+	slow := tree.AddLazyNode(root, "slow", func(node *TreeNode, done func([]*TreeNode, error)) {
+		pending = done
+	})
+	fast := tree.AddLazyNode(root, "fast", func(node *TreeNode, done func([]*TreeNode, error)) {
+		done([]*TreeNode{{Text: "child"}}, nil)
+	})
+
+	tree.LoadChildren(slow)
+	pending([]*TreeNode{{Text: "late"}}, nil)
+
+	// A synchronous load applies only its own result
+	tree.LoadChildren(fast)
+	if len(fast.Children) != 1 {
+		t.Errorf("Expected the synchronous result applied, got %d children", len(fast.Children))
+	}
+	if !slow.Loading() || len(slow.Children) != 0 {
+		t.Error("Expected the other node's result to wait for ApplyLoaded")
+	}
+	if !tree.ApplyLoaded() || len(slow.Children) != 1 {
+		t.Error("Expected ApplyLoaded to apply the waiting result")
+	}
+}
@@ -0,0 +1,150 @@
+package widgets
+
+import "github.com/gdamore/tcell/v2"
+
+// NodeMove describes a node moved to another place in the tree. Indexes
+// are positions among the parent's children: OldIndex before the move and
+// NewIndex after it.
+type NodeMove struct {
+	Node      *TreeNode
+	OldParent *TreeNode
+	OldIndex  int
+	NewParent *TreeNode
+	NewIndex  int
+}
+
+// indexOf returns the position of node among its siblings, or -1
+func indexOf(node *TreeNode) int {
+	if node == nil || node.Parent == nil {
+		return -1
+	}
+	for i, child := range node.Parent.Children {
+		if child == node {
+			return i
+		}
+	}
+	return -1
+}
+
+// MoveNode moves a node and its children below parent at index, which is
+// clamped to the parent's children. It fails for the root, for moves into
+// the node's own subtree and for parents whose children have not loaded
+// yet. The node stays selected if it was, and OnMove is called.
+func (t *TreeView) MoveNode(node, parent *TreeNode, index int) bool {
+	if node == nil || parent == nil || node == t.Root {
+		return false
+	}
+	oldIndex := indexOf(node)
+	if oldIndex < 0 {
+		return false
+	}
+	for p := parent; p != nil; p = p.Parent {
+		if p == node {
+			return false
+		}
+	}
+	if parent.Loader != nil && parent.load != loadDone {
+		return false
+	}
+
+	oldParent := node.Parent
+	size := len(parent.Children)
+	if parent == oldParent {
+		size-- // the node leaves its place first
+	}
+	if index < 0 {
+		index = 0
+	}
+	if index > size {
+		index = size
+	}
+	if parent == oldParent && index == oldIndex {
+		return false
+	}
+
+	oldParent.Children = append(oldParent.Children[:oldIndex:oldIndex], oldParent.Children[oldIndex+1:]...)
+	parent.Children = append(parent.Children[:index:index], append([]*TreeNode{node}, parent.Children[index:]...)...)
+	node.Parent = parent
+
+	// Keep the ancestors' check flags in step with their new children
+	for _, chain := range []*TreeNode{oldParent, parent} {
+		for p := chain; p != nil; p = p.Parent {
+			if len(p.Children) > 0 {
+				p.Checked = t.CheckState(p) == Checked
+			}
+		}
+	}
+	t.refilter()
+	if t.Selected == node {
+		t.EnsureVisible()
+	}
+	if t.OnMove != nil {
+		t.OnMove(NodeMove{Node: node, OldParent: oldParent, OldIndex: oldIndex, NewParent: parent, NewIndex: index})
+	}
+	return true
+}
+
+// MoveNodeUp swaps a node with the sibling above it
+func (t *TreeView) MoveNodeUp(node *TreeNode) bool {
+	i := indexOf(node)
+	if i <= 0 {
+		return false
+	}
+	return t.MoveNode(node, node.Parent, i-1)
+}
+
+// MoveNodeDown swaps a node with the sibling below it
+func (t *TreeView) MoveNodeDown(node *TreeNode) bool {
+	i := indexOf(node)
+	if i < 0 || i == len(node.Parent.Children)-1 {
+		return false
+	}
+	return t.MoveNode(node, node.Parent, i+1)
+}
+
+// IndentNode makes a node the last child of the sibling above it, and
+// expands that sibling
+func (t *TreeView) IndentNode(node *TreeNode) bool {
+	i := indexOf(node)
+	if i <= 0 {
+		return false
+	}
+	sibling := node.Parent.Children[i-1]
+	if !t.MoveNode(node, sibling, len(sibling.Children)) {
+		return false
+	}
+	sibling.Expanded = true
+	return true
+}
+
+// OutdentNode makes a node the sibling following its parent
+func (t *TreeView) OutdentNode(node *TreeNode) bool {
+	if node == nil || node.Parent == nil || node.Parent.Parent == nil {
+		return false
+	}
+	parent := node.Parent
+	return t.MoveNode(node, parent.Parent, indexOf(parent)+1)
+}
+
+// handleMoveKey handles the Alt+arrow reordering keys. ok is false for
+// other keys. Nodes are not moved while filtered, as hidden siblings would
+// make the moves hard to follow.
+func (t *TreeView) handleMoveKey(ev *tcell.EventKey) (handled, ok bool) {
+	var move func(node *TreeNode) bool
+	switch ev.Key() {
+	case tcell.KeyUp:
+		move = t.MoveNodeUp
+	case tcell.KeyDown:
+		move = t.MoveNodeDown
+	case tcell.KeyRight:
+		move = t.IndentNode
+	case tcell.KeyLeft:
+		move = t.OutdentNode
+	default:
+		return false, false
+	}
+	if t.Filtered() {
+		return false, true
+	}
+	return move(t.Selected), true
+}
@@ -0,0 +1,184 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// childTexts returns the texts of a node's children
+func childTexts(node *TreeNode) []string {
+	texts := make([]string, len(node.Children))
+	for i, child := range node.Children {
+		texts[i] = child.Text
+	}
+	return texts
+}
+
+func TestTreeViewMoveNode(t *testing.T) {
+	t.Parallel()
+	screen := createTestScreen(t)
+	tree := NewTreeView(screen, 0, 0, 30, 10)
+
+	root := tree.AddNode(nil, "pipeline")
+	build := tree.AddNode(root, "build")
+	test := tree.AddNode(root, "test")
+	deploy := tree.AddNode(root, "deploy")
+	lint := tree.AddNode(build, "lint")
+
+	var moves []NodeMove
+	tree.OnMove = func(move NodeMove) {
+		moves = append(moves, move)
+	}
+
+	if !tree.MoveNode(deploy, root, 0) {
+		t.Fatal("Expected deploy to move")
+	}
+	if got := childTexts(root); !equalStrings(got, []string{"deploy", "build", "test"}) {
+		t.Errorf("Unexpected order %v", got)
+	}
+	want := NodeMove{Node: deploy, OldParent: root, OldIndex: 2, NewParent: root, NewIndex: 0}
+	if len(moves) != 1 || moves[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, moves)
+	}
+
+	// Indexes past the end append
+	if !tree.MoveNode(test, build, 10) || test.Parent != build {
+		t.Fatal("Expected test to move below build")
+	}
+	if got := childTexts(build); !equalStrings(got, []string{"lint", "test"}) {
+		t.Errorf("Unexpected children %v", got)
+	}
+
+	// Moves that change nothing or break the tree fail
+	if tree.MoveNode(lint, build, 0) {
+		t.Error("Expected moving a node onto itself to fail")
+	}
+	if tree.MoveNode(build, lint, 0) {
+		t.Error("Expected moving a node into its subtree to fail")
+	}
+	if tree.MoveNode(root, build, 0) {
+		t.Error("Expected moving the root to fail")
+	}
+	lazy := tree.AddLazyNode(root, "remote", func(node *TreeNode, done func([]*TreeNode, error)) {})
+	if tree.MoveNode(lint, lazy, 0) {
+		t.Error("Expected moving into unloaded children to fail")
+	}
+	if len(moves) != 2 {
+		t.Errorf("Expected 2 moves, got %d", len(moves))
+	}
+}
+
+func TestTreeViewMoveKeys(t *testing.T) {
+	t.Parallel()
+	screen := createTestScreen(t)
+	screen.SetSize(30, 10)
+	tree := NewTreeView(screen, 0, 0, 30, 10)
+
+	root := tree.AddNode(nil, "menu")
+	file := tree.AddNode(root, "File")
+	edit := tree.AddNode(root, "Edit")
+	tree.AddNode(root, "View")
+	tree.ExpandAll()
+	tree.Selected = edit
+
+	key := func(k tcell.Key) bool {
+		return tree.HandleKeyEvent(tcell.NewEventKey(k, 0, tcell.ModAlt))
+	}
+
+	// The keys only move nodes when enabled
+	if key(tcell.KeyUp); file.Parent != root || indexOf(edit) != 1 {
+		t.Fatal("Expected no move without Reorderable")
+	}
+	tree.Reorderable = true
+	tree.Selected = edit
+
+	if !key(tcell.KeyUp) || indexOf(edit) != 0 {
+		t.Errorf("Expected Alt+Up to move Edit first, got %v", childTexts(root))
+	}
+	if key(tcell.KeyUp) {
+		t.Error("Expected Alt+Up on the first sibling to do nothing")
+	}
+	if !key(tcell.KeyDown) || indexOf(edit) != 1 {
+		t.Errorf("Expected Alt+Down to move Edit back, got %v", childTexts(root))
+	}
+
+	// Indenting makes it the last child of the sibling above
+	if !key(tcell.KeyRight) || edit.Parent != file || !file.Expanded {
+		t.Fatal("Expected Alt+Right to move Edit below File")
+	}
+	if tree.Selected != edit {
+		t.Error("Expected the moved node to stay selected")
+	}
+	tree.Draw()
+	want := []string{"- menu", "├── - File", "│   └── Edit", "└── View"}
+	for i, line := range want {
+		if got := readRow(screen, 0, i, len([]rune(line))); got != line {
+			t.Errorf("Row %d: expected %q, got %q", i, line, got)
+		}
+	}
+
+	// Outdenting puts it after its old parent
+	if !key(tcell.KeyLeft) || edit.Parent != root || indexOf(edit) != 1 {
+		t.Errorf("Expected Alt+Left to move Edit after File, got %v", childTexts(root))
+	}
+	if key(tcell.KeyLeft) {
+		t.Error("Expected a child of the root not to outdent")
+	}
+
+	// Nodes are not moved while filtered
+	tree.SetFilter("e")
+	tree.Selected = edit
+	if key(tcell.KeyUp) || indexOf(edit) != 1 {
+		t.Error("Expected no move while filtered")
+	}
+}
+
+func TestTreeViewMoveUpdatesChecks(t *testing.T) {
+	t.Parallel()
+	screen := createTestScreen(t)
+	tree := NewTreeView(screen, 0, 0, 30, 10)
+	tree.SetCheckable(true)
+
+	root := tree.AddNode(nil, "root")
+	a := tree.AddNode(root, "a")
+	a1 := tree.AddNode(a, "a1")
+	a2 := tree.AddNode(a, "a2")
+	b := tree.AddNode(root, "b")
+	tree.SetChecked(a1, true)
+	if a.Checked {
+		t.Fatal("Expected a to be partially checked")
+	}
+
+	// Moving the unchecked child out leaves a fully checked
+	tree.MoveNode(a2, b, 0)
+	if !a.Checked || tree.CheckState(a) != Checked {
+		t.Error("Expected a to be checked after a2 moved away")
+	}
+	if b.Checked {
+		t.Error("Expected b to take its state from a2")
+	}
+}
+
+func TestTreeViewRemoveSelected(t *testing.T) {
+	t.Parallel()
+	screen := createTestScreen(t)
+	tree := NewTreeView(screen, 0, 0, 30, 10)
+
+	root := tree.AddNode(nil, "menu")
+	file := tree.AddNode(root, "File")
+	open := tree.AddNode(file, "Open")
+	tree.AddNode(root, "Edit")
+	tree.SetFilter("e")
+
+	// Removing an ancestor of the selection selects the parent of the
+	// removed node, which the filter shows
+	tree.Selected = open
+	tree.RemoveNode(file)
+	if tree.Selected != root {
+		t.Errorf("Expected menu selected, got %v", tree.Selected)
+	}
+	if got := tree.Matches(); len(got) != 2 {
+		t.Errorf("Expected the removed nodes to stop matching, got %d matches", len(got))
+	}
+}
@@ -0,0 +1,124 @@
+package widgets
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestTreeViewGuides(t *testing.T) {
+	t.Parallel()
+	screen := createTestScreen(t)
+	screen.SetSize(30, 10)
+	tree := NewTreeView(screen, 0, 0, 30, 10)
+
+	root := tree.AddNode(nil, "/")
+	etc := tree.AddNode(root, "etc")
+	tree.AddNode(etc, "hosts")
+	tree.AddNode(etc, "passwd")
+	tree.AddNode(root, "var")
+	tree.ExpandAll()
+	tree.Selected = nil
+	tree.Draw()
+
+	want := []string{
+		"- /",
+		"├── - etc",
+		"│   ├── hosts",
+		"│   └── passwd",
+		"└── var",
+	}
+	for i, line := range want {
+		if got := readRow(screen, 0, i, len([]rune(line))); got != line {
+			t.Errorf("Row %d: expected %q, got %q", i, line, got)
+		}
+	}
+
+	// Guides come from the style
+	style := DefaultTreeViewStyle()
+	style.Guides = ASCIITreeGuides()
+	tree.SetStyle(style)
+	screen.Clear()
+	tree.Draw()
+	if got := readRow(screen, 0, 3, 14); got != "|   `-- passwd" {
+		t.Errorf("Expected ASCII guides, got %q", got)
+	}
+}
+
+func TestTreeViewIconsAndBadges(t *testing.T) {
+	t.Parallel()
+	screen := createTestScreen(t)
+	screen.SetSize(20, 5)
+	tree := NewTreeView(screen, 0, 0, 20, 5)
+	tree.SetShowLines(false)
+
+	root := tree.AddNode(nil, "src")
+	main := tree.AddNode(root, "main.go")
+	tree.AddNode(root, "a-very-long-file-name.go")
+	tree.ExpandAll()
+
+	sizes := map[*TreeNode]int{main: 512}
+	tree.Icon = func(node *TreeNode) string {
+		if len(node.Children) > 0 {
+			return "D"
+		}
+		return "F"
+	}
+	tree.Badge = func(node *TreeNode) string {
+		if len(node.Children) > 0 {
+			return strconv.Itoa(len(node.Children))
+		}
+		return strconv.Itoa(sizes[node])
+	}
+	tree.Draw()
+
+	if got := readRow(screen, 0, 0, 20); got != "- D src            2" {
+		t.Errorf("Row 0: got %q", got)
+	}
+	if got := readRow(screen, 0, 1, 20); got != "    F main.go    512" {
+		t.Errorf("Row 1: got %q", got)
+	}
+	// Long text is clipped to keep a space before the badge
+	if got := readRow(screen, 0, 2, 20); got != "    F a-very-long- 0" {
+		t.Errorf("Row 2: got %q", got)
+	}
+}
+
+func TestTreeViewFullRowHighlight(t *testing.T) {
+	t.Parallel()
+	screen := createTestScreen(t)
+	screen.SetSize(20, 5)
+	tree := NewTreeView(screen, 0, 0, 20, 5)
+	tree.SelectedStyle = tcell.StyleDefault.Background(tcell.ColorBlue)
+
+	root := tree.AddNode(nil, "root")
+	child := tree.AddNode(root, "child")
+	tree.ExpandAll()
+	tree.Selected = child
+	tree.Draw()
+
+	for _, x := range []int{0, 6, 19} {
+		_, _, style, _ := screen.GetContent(x, 1)
+		if _, bg, _ := style.Decompose(); bg != tcell.ColorBlue {
+			t.Errorf("Expected the selection background at column %d", x)
+		}
+	}
+	_, _, style, _ := screen.GetContent(19, 0)
+	if _, bg, _ := style.Decompose(); bg == tcell.ColorBlue {
+		t.Error("Expected unselected rows without the selection background")
+	}
+
+	// Without it only the node text is reversed
+	tree.FullRowHighlight = false
+	screen.Clear()
+	tree.Draw()
+	_, _, style, _ = screen.GetContent(19, 1)
+	if _, bg, _ := style.Decompose(); bg == tcell.ColorBlue {
+		t.Error("Expected the highlight to stop at the text")
+	}
+	_, _, style, _ = screen.GetContent(4, 1)
+	if _, _, attr := style.Decompose(); attr&tcell.AttrReverse == 0 {
+		t.Error("Expected the node text reversed")
+	}
+}
@@ -1,6 +1,9 @@
 package widgets
 
 import (
+	"strings"
+
+	"github.com/deadjoe/termdodo/symbols"
 	theme "github.com/deadjoe/termdodo/theme"
 	tcell "github.com/gdamore/tcell/v2"
 )
@@ -13,6 +16,15 @@ type TreeNode struct {
 	Expanded bool
 	Data     interface{} // Optional data associated with the node
 	Style    tcell.Style
+	// Loader, when set, loads Children the first time the node is expanded
+	Loader NodeLoader
+	// Checked is the checkbox state of a node without children, see
+	// TreeView.CheckState
+	Checked bool
+
+	load    loadState
+	loadErr error
+	loadGen int // incremented on every load so stale results are dropped
 }
 
 // TreeView represents a tree view widget
@@ -22,6 +34,12 @@ type TreeView struct {
 	Screen        tcell.Screen
 	Style         tcell.Style
 	SelectedStyle tcell.Style
+	LineStyle     tcell.Style // style of the guides
+	BadgeStyle    tcell.Style
+	MatchStyle    tcell.Style // highlights characters matched by the filter
+	CheckedStyle  tcell.Style
+	LoadingStyle  tcell.Style
+	ErrorStyle    tcell.Style
 
 	Root         *TreeNode
 	Selected     *TreeNode
@@ -30,15 +48,82 @@ type TreeView struct {
 
 	ShowLines bool
 	Indent    int
+	Guides    TreeGuides
+	// ExpandedIcon and CollapsedIcon are drawn before nodes with children
+	ExpandedIcon  rune
+	CollapsedIcon rune
+	// FullRowHighlight highlights the selected node across the whole width
+	// in SelectedStyle instead of reversing its text
+	FullRowHighlight bool
+
+	// Icon, when set, returns the icon drawn before a node's text, e.g. a
+	// folder or a process state. An empty icon takes no space.
+	Icon func(node *TreeNode) string
+	// Badge, when set, returns text drawn right-aligned on a node's row,
+	// e.g. a size or a count
+	Badge func(node *TreeNode) string
+
+	// TypeAhead jumps to the node starting with the characters typed. A
+	// prefix starts with a letter or digit not bound to another key.
+	TypeAhead bool
+	typeAhead typeAhead
+	loads     loadQueue
+
+	// Filter is the fuzzy query narrowing the shown nodes, see SetFilter
+	Filter     string
+	matches    []*TreeNode
+	positions  map[*TreeNode][]int // matched rune positions in each match
+	keep       map[*TreeNode]bool  // matches and their ancestors
+	prompt     bool
+	promptText string
+
+	// Checkable draws checkboxes that Space toggles, see SetChecked
+	Checkable bool
+	// OnCheck is called with the node the user checked or unchecked
+	OnCheck func(node *TreeNode, state CheckState)
+
+	// Reorderable lets Alt+Up and Alt+Down move the selected node among its
+	// siblings and Alt+Right and Alt+Left indent and outdent it
+	Reorderable bool
+	// OnMove is called after a node was moved, see MoveNode
+	OnMove func(move NodeMove)
 }
 
 // TreeViewStyle represents the style configuration for the tree view
 type TreeViewStyle struct {
-	NodeStyle      tcell.Style // Style for normal nodes
-	SelectedStyle  tcell.Style // Style for selected node
-	LineStyle      tcell.Style // Style for tree lines
-	ExpandedIcon   rune        // Icon for expanded nodes
-	CollapsedIcon  rune        // Icon for collapsed nodes
+	NodeStyle     tcell.Style // Style for normal nodes
+	SelectedStyle tcell.Style // Style for selected node
+	LineStyle     tcell.Style // Style for tree lines
+	BadgeStyle    tcell.Style // Style for badges
+	ExpandedIcon  rune        // Icon for expanded nodes
+	CollapsedIcon rune        // Icon for collapsed nodes
+	Guides        TreeGuides  // Characters for tree lines
+}
+
+// TreeGuides are the characters tree lines are drawn with. Each level is
+// Indent+2 cells wide: a branch or last-child corner, Indent horizontal
+// lines and a space, or a vertical line continuing past the level.
+type TreeGuides struct {
+	Vertical   rune // │, continues a parent's line past a child
+	Branch     rune // ├, joins a child that has siblings below it
+	Last       rune // └, joins the last child
+	Horizontal rune // ─, leads from the line to the child
+}
+
+// DefaultTreeGuides returns box-drawing guides: ├── └── │
+func DefaultTreeGuides() TreeGuides {
+	return TreeGuides{
+		Vertical:   symbols.VLine,
+		Branch:     symbols.DivLeft,
+		Last:       symbols.BLCorner,
+		Horizontal: symbols.HLine,
+	}
+}
+
+// ASCIITreeGuides returns guides for terminals without box-drawing
+// characters: |-- `--
+func ASCIITreeGuides() TreeGuides {
+	return TreeGuides{Vertical: '|', Branch: '|', Last: '`', Horizontal: '-'}
 }
 
 // DefaultTreeViewStyle returns the default style configuration
@@ -47,8 +132,10 @@ func DefaultTreeViewStyle() TreeViewStyle {
 		NodeStyle:     theme.GetStyle(theme.ColorToHex(theme.Current.MainFg), theme.ColorToHex(theme.Current.MainBg)),
 		SelectedStyle: theme.GetStyle(theme.ColorToHex(theme.Current.Selected), theme.ColorToHex(theme.Current.HighlightBg)),
 		LineStyle:     theme.GetStyle(theme.ColorToHex(theme.Current.MainFg), theme.ColorToHex(theme.Current.MainBg)),
+		BadgeStyle:    theme.Current.GetStyle().Dim(true),
 		ExpandedIcon:  '-',
 		CollapsedIcon: '+',
+		Guides:        DefaultTreeGuides(),
 	}
 }
 
@@ -62,8 +149,20 @@ func NewTreeView(screen tcell.Screen, x, y, width, height int) *TreeView {
 		Screen:        screen,
 		Style:         theme.GetStyle(theme.ColorToHex(theme.Current.MainFg), theme.ColorToHex(theme.Current.MainBg)),
 		SelectedStyle: theme.GetStyle(theme.ColorToHex(theme.Current.Selected), theme.ColorToHex(theme.Current.HighlightBg)),
-		ShowLines:     true,
-		Indent:        2,
+		LineStyle:     theme.GetStyle(theme.ColorToHex(theme.Current.MainFg), theme.ColorToHex(theme.Current.MainBg)),
+		BadgeStyle:    theme.Current.GetStyle().Dim(true),
+		MatchStyle: theme.Current.GetStyle().
+			Background(theme.Current.Accent).
+			Foreground(theme.Current.HighlightFg),
+		CheckedStyle:     theme.Current.GetStyle().Foreground(theme.Current.Selected),
+		LoadingStyle:     theme.Current.GetStyle().Dim(true),
+		ErrorStyle:       theme.Current.GetStyle().Foreground(tcell.ColorRed),
+		ShowLines:        true,
+		Indent:           2,
+		Guides:           DefaultTreeGuides(),
+		ExpandedIcon:     '-',
+		CollapsedIcon:    '+',
+		FullRowHighlight: true,
 	}
 }
 
@@ -72,6 +171,7 @@ func (t *TreeView) SetRoot(root *TreeNode) {
 	t.Root = root
 	t.Selected = nil
 	t.ScrollOffset = 0
+	t.refilter()
 }
 
 // Draw draws the tree view
@@ -80,8 +180,12 @@ func (t *TreeView) Draw() {
 		return
 	}
 
+	t.ApplyLoaded()
 	t.VisibleNodes = 0
-	t.drawNode(t.Root, t.X, t.Y-t.ScrollOffset, false)
+	t.drawNode(t.Root, "", true, t.Y-t.ScrollOffset)
+	if t.prompt {
+		t.drawPrompt()
+	}
 }
 
 // getNodeStyle returns the appropriate style for a node
@@ -91,78 +195,177 @@ func (t *TreeView) getNodeStyle(node *TreeNode) tcell.Style {
 		style = node.Style
 	}
 	if node == t.Selected {
+		if t.FullRowHighlight {
+			return t.SelectedStyle
+		}
 		style = style.Reverse(true)
 	}
 	return style
 }
 
-// drawNode recursively draws a node and its children
-func (t *TreeView) drawNode(node *TreeNode, x, y int, isLast bool) int {
-	if y >= t.Y+t.Height {
+// guide returns the guide drawn for one level: the connector to a node if
+// join is true, and otherwise the line continuing past it
+func (t *TreeView) guide(join, last bool) string {
+	width := t.Indent + 2
+	if width < 2 {
+		width = 2
+	}
+	if !t.ShowLines {
+		return strings.Repeat(" ", width)
+	}
+	switch {
+	case join && last:
+		return string(t.Guides.Last) + strings.Repeat(string(t.Guides.Horizontal), width-2) + " "
+	case join:
+		return string(t.Guides.Branch) + strings.Repeat(string(t.Guides.Horizontal), width-2) + " "
+	case last:
+		return strings.Repeat(" ", width)
+	}
+	return string(t.Guides.Vertical) + strings.Repeat(" ", width-1)
+}
+
+// drawNode recursively draws a node and its children. prefix holds the
+// guides of the node's ancestors.
+func (t *TreeView) drawNode(node *TreeNode, prefix string, isLast bool, y int) int {
+	if y >= t.Y+t.rows() {
 		return y
 	}
 
+	guides := prefix
+	if node != t.Root {
+		guides += t.guide(true, isLast)
+	}
 	if y >= t.Y {
 		t.VisibleNodes++
-		// Draw node content
-		style := t.getNodeStyle(node)
+		t.drawRow(node, guides, y)
+	}
+	y++
 
-		// Draw tree lines
-		if t.ShowLines && node != t.Root {
-			for i := t.X; i < x-t.Indent; i += t.Indent {
-				t.Screen.SetContent(i, y, '│', nil, t.Style)
-			}
-			if isLast {
-				t.Screen.SetContent(x-t.Indent, y, '└', nil, t.Style)
-			} else {
-				t.Screen.SetContent(x-t.Indent, y, '├', nil, t.Style)
-			}
-			for i := x - t.Indent + 1; i < x; i++ {
-				t.Screen.SetContent(i, y, '─', nil, t.Style)
-			}
+	childPrefix := prefix
+	if node != t.Root {
+		childPrefix += t.guide(false, isLast)
+	}
+	if t.isOpen(node) {
+		children := t.shownChildren(node)
+		for i, child := range children {
+			y = t.drawNode(child, childPrefix, i == len(children)-1, y)
 		}
+	}
+	if node.Expanded && !t.Filtered() {
+		y = t.drawLoadStatus(node, childPrefix+t.guide(false, true), y)
+	}
+	return y
+}
 
-		// Draw expand/collapse indicator
-		if len(node.Children) > 0 {
-			if node.Expanded {
-				t.Screen.SetContent(x, y, '-', nil, style)
-			} else {
-				t.Screen.SetContent(x, y, '+', nil, style)
-			}
-			x += 2
+// drawRow draws a node's line: guides, expand icon, checkbox, icon, text
+// and badge
+func (t *TreeView) drawRow(node *TreeNode, guides string, y int) {
+	style := t.getNodeStyle(node)
+	lineStyle := t.LineStyle
+	badgeStyle := t.BadgeStyle
+	right := t.X + t.Width
+	if node == t.Selected && t.FullRowHighlight {
+		lineStyle, badgeStyle = style, style
+		for x := t.X; x < right; x++ {
+			t.Screen.SetContent(x, y, ' ', nil, style)
 		}
+	}
 
-		// Draw node text
-		for i, r := range node.Text {
-			if x+i >= t.X+t.Width {
-				break
+	x := t.X
+	put := func(text string, style tcell.Style) {
+		for _, r := range text {
+			if x >= right {
+				return
 			}
-			t.Screen.SetContent(x+i, y, r, nil, style)
+			t.Screen.SetContent(x, y, r, nil, style)
+			x++
 		}
 	}
+	put(guides, lineStyle)
 
-	y++
+	if node.hasChildren() {
+		icon := t.CollapsedIcon
+		if t.isOpen(node) {
+			icon = t.ExpandedIcon
+		}
+		put(string(icon)+" ", style)
+	}
+	if t.Checkable {
+		x = t.drawCheckbox(node, x, y, style)
+	}
+	if t.Icon != nil {
+		if icon := t.Icon(node); icon != "" {
+			put(icon+" ", style)
+		}
+	}
+
+	// Keep the badge clear of the text
+	var badge []rune
+	if t.Badge != nil {
+		badge = []rune(t.Badge(node))
+	}
+	textRight := right
+	if len(badge) > 0 {
+		textRight = right - len(badge) - 1
+	}
 
-	if node.Expanded {
-		childX := x + t.Indent
-		for i, child := range node.Children {
-			isLastChild := i == len(node.Children)-1
-			y = t.drawNode(child, childX, y, isLastChild)
+	// Draw node text, highlighting the characters the filter matched
+	positions := t.positions[node]
+	for i, r := range []rune(node.Text) {
+		if x >= textRight {
+			break
+		}
+		cellStyle := style
+		if len(positions) > 0 && positions[0] == i {
+			cellStyle = t.MatchStyle
+			positions = positions[1:]
 		}
+		t.Screen.SetContent(x, y, r, nil, cellStyle)
+		x++
 	}
 
-	return y
+	if len(badge) > 0 && right-len(badge) > x {
+		for i, r := range badge {
+			t.Screen.SetContent(right-len(badge)+i, y, r, nil, badgeStyle)
+		}
+	}
 }
 
-// HandleKeyEvent handles keyboard events for the tree view
+// HandleKeyEvent handles keyboard events for the tree view. Pressing /
+// opens a prompt that fuzzy-filters nodes as you type, and n and N cycle
+// through the matches while a filter is active. In checkable trees, Space
+// toggles the selected node's checkbox. With TypeAhead enabled, letters
+// and digits not bound to these keys jump to the next visible node
+// starting with what was typed; once a prefix is started, every character
+// continues it while nodes match.
 func (t *TreeView) HandleKeyEvent(event *tcell.EventKey) bool {
 	if t.Root == nil {
 		return false
 	}
 
+	t.ApplyLoaded()
+	t.loadExpanded()
 	if t.Selected == nil {
 		t.Selected = t.Root
 	}
+	if t.prompt {
+		return t.handlePromptKey(event)
+	}
+	if t.handleTypeAhead(event) {
+		return true
+	}
+	if t.handleFilterKey(event) {
+		return true
+	}
+	if t.Checkable && event.Key() == tcell.KeyRune && event.Rune() == ' ' {
+		t.ToggleChecked(t.Selected)
+		return true
+	}
+	if t.Reorderable && event.Modifiers()&tcell.ModAlt != 0 {
+		if handled, ok := t.handleMoveKey(event); ok {
+			return handled
+		}
+	}
 
 	switch event.Key() {
 	case tcell.KeyUp:
@@ -171,15 +374,15 @@ func (t *TreeView) HandleKeyEvent(event *tcell.EventKey) bool {
 		return t.SelectNext()
 	case tcell.KeyLeft:
 		if t.Selected.Expanded {
-			t.Selected.Expanded = false
+			t.setExpanded(t.Selected, false)
 			return true
 		} else if t.Selected.Parent != nil {
 			t.Selected = t.Selected.Parent
 			return true
 		}
 	case tcell.KeyRight:
-		if !t.Selected.Expanded && len(t.Selected.Children) > 0 {
-			t.Selected.Expanded = true
+		if !t.Selected.Expanded && t.Selected.hasChildren() {
+			t.setExpanded(t.Selected, true)
 			return true
 		} else if t.Selected.Expanded && len(t.Selected.Children) > 0 {
 			t.Selected = t.Selected.Children[0]
@@ -204,6 +407,10 @@ func (t *TreeView) SelectNext() bool {
 		return false
 	}
 
+	if t.Filtered() {
+		return t.step(1)
+	}
+
 	// If we're at a leaf node, we can't go further down
 	if t.Selected.isLeafNode() {
 		return false
@@ -252,6 +459,10 @@ func (t *TreeView) SelectPrevious() bool {
 		return false
 	}
 
+	if t.Filtered() {
+		return t.step(-1)
+	}
+
 	// If at root, cannot go up further
 	if t.Selected == t.Root {
 		return false
@@ -311,13 +522,15 @@ func (t *TreeView) CollapseAll() {
 	t.collapseNode(t.Root)
 }
 
-// expandNode recursively expands a node and its children
+// expandNode recursively expands a node and its children. Lazy nodes
+// start loading, but children they load aren't expanded.
 func (t *TreeView) expandNode(node *TreeNode) {
 	if node == nil {
 		return
 	}
-	node.Expanded = true
-	for _, child := range node.Children {
+	children := node.Children
+	t.setExpanded(node, true)
+	for _, child := range children {
 		t.expandNode(child)
 	}
 }
@@ -357,6 +570,7 @@ func (t *TreeView) AddNode(parent *TreeNode, text string) *TreeNode {
 	if t.Selected == nil {
 		t.Selected = node
 	}
+	t.refilter()
 
 	return node
 }
@@ -371,9 +585,18 @@ func (t *TreeView) RemoveNode(node *TreeNode) {
 	if node == t.Root {
 		t.Root = nil
 		t.Selected = nil
+		t.refilter()
 		return
 	}
 
+	// Select the parent if the selected node is being removed
+	for n := t.Selected; n != nil; n = n.Parent {
+		if n == node {
+			t.Selected = node.Parent
+			break
+		}
+	}
+
 	// Find and remove the node from its parent's children
 	if node.Parent != nil {
 		children := node.Parent.Children
@@ -384,12 +607,6 @@ func (t *TreeView) RemoveNode(node *TreeNode) {
 
 				// Clear the parent reference
 				node.Parent = nil
-
-				// If the removed node was selected, select the parent
-				if node == t.Selected {
-					t.Selected = node.Parent
-				}
-
 				break
 			}
 		}
@@ -407,12 +624,13 @@ func (t *TreeView) RemoveNode(node *TreeNode) {
 		}
 	}
 	clearParents(node)
+	t.refilter()
 }
 
 // ExpandSelected expands the currently selected node
 func (t *TreeView) ExpandSelected() bool {
 	if t.Selected != nil {
-		t.Selected.Expanded = true
+		t.setExpanded(t.Selected, true)
 		return true
 	}
 	return false
@@ -421,7 +639,7 @@ func (t *TreeView) ExpandSelected() bool {
 // CollapseSelected collapses the currently selected node
 func (t *TreeView) CollapseSelected() bool {
 	if t.Selected != nil {
-		t.Selected.Expanded = false
+		t.setExpanded(t.Selected, false)
 		return true
 	}
 	return false
@@ -430,7 +648,7 @@ func (t *TreeView) CollapseSelected() bool {
 // ToggleSelected toggles the expanded state of the currently selected node
 func (t *TreeView) ToggleSelected() bool {
 	if t.Selected != nil {
-		t.Selected.Expanded = !t.Selected.Expanded
+		t.setExpanded(t.Selected, !t.Selected.Expanded)
 		return true
 	}
 	return false
@@ -449,42 +667,7 @@ func (t *TreeView) EnsureVisible() {
 		current = current.Parent
 	}
 
-	// Calculate node position
-	y := 0
-	var calcPosition func(*TreeNode) int
-	calcPosition = func(node *TreeNode) int {
-		if node == t.Selected {
-			return y
-		}
-		if node == nil {
-			return -1
-		}
-		y++
-		if node.Expanded {
-			for _, child := range node.Children {
-				if result := calcPosition(child); result >= 0 {
-					return result
-				}
-			}
-		}
-		return -1
-	}
-
-	pos := calcPosition(t.Root)
-	if pos < 0 {
-		return
-	}
-
-	// Adjust scroll offset if needed
-	if pos < t.ScrollOffset {
-		t.ScrollOffset = pos
-	} else if pos >= t.ScrollOffset+t.Height {
-		t.ScrollOffset = pos - t.Height + 1
-	}
-
-	if t.ScrollOffset < 0 {
-		t.ScrollOffset = 0
-	}
+	t.scrollToSelected()
 }
 
 // ScrollTo scrolls the view to the specified offset
@@ -504,6 +687,17 @@ func (t *TreeView) ScrollBy(delta int) {
 func (t *TreeView) SetStyle(style TreeViewStyle) {
 	t.Style = style.NodeStyle
 	t.SelectedStyle = style.SelectedStyle
+	t.LineStyle = style.LineStyle
+	t.BadgeStyle = style.BadgeStyle
+	if style.ExpandedIcon != 0 {
+		t.ExpandedIcon = style.ExpandedIcon
+	}
+	if style.CollapsedIcon != 0 {
+		t.CollapsedIcon = style.CollapsedIcon
+	}
+	if style.Guides != (TreeGuides{}) {
+		t.Guides = style.Guides
+	}
 	// Update all existing nodes with the new style
 	var updateStyles func(*TreeNode)
 	updateStyles = func(node *TreeNode) {
@@ -0,0 +1,184 @@
+package widgets
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// typeAheadTimeout is how long after the last key typing starts a new
+// prefix
+const typeAheadTimeout = time.Second
+
+// typeAhead collects the characters typed in quick succession to jump to
+// the row or node starting with them
+type typeAhead struct {
+	prefix string
+	last   time.Time
+	now    func() time.Time
+}
+
+// active returns whether the next key continues the current prefix
+func (ta *typeAhead) active() bool {
+	return ta.prefix != "" && ta.clock().Sub(ta.last) < typeAheadTimeout
+}
+
+// add appends r to the prefix, starting a new one if the last key was too
+// long ago, and returns the lowercased prefix
+func (ta *typeAhead) add(r rune) string {
+	if !ta.active() {
+		ta.prefix = ""
+	}
+	ta.prefix += strings.ToLower(string(r))
+	ta.last = ta.clock()
+	return ta.prefix
+}
+
+// reset clears the prefix
+func (ta *typeAhead) reset() {
+	ta.prefix = ""
+}
+
+// clock returns the current time
+func (ta *typeAhead) clock() time.Time {
+	if ta.now != nil {
+		return ta.now()
+	}
+	return time.Now()
+}
+
+// typeAheadRune returns the character to add to the prefix, or false for
+// keys type-ahead leaves to other bindings. Only letters and digits that
+// bound doesn't report as key bindings start a prefix; once started, it
+// takes every character.
+func (ta *typeAhead) typeAheadRune(ev *tcell.EventKey, bound func(r rune) bool) (rune, bool) {
+	if ev.Key() != tcell.KeyRune || ev.Modifiers()&(tcell.ModCtrl|tcell.ModAlt) != 0 {
+		return 0, false
+	}
+	r := ev.Rune()
+	if !ta.active() && (!unicode.IsLetter(r) && !unicode.IsDigit(r) || bound(r)) {
+		return 0, false
+	}
+	return r, true
+}
+
+// findPrefix returns the first of count items, starting at start and
+// wrapping around, whose text starts with prefix, or -1
+func findPrefix(count, start int, prefix string, text func(i int) string) int {
+	for n := 0; n < count; n++ {
+		i := (start + n) % count
+		if strings.HasPrefix(strings.ToLower(text(i)), prefix) {
+			return i
+		}
+	}
+	return -1
+}
+
+// typeAheadColumn returns the column type-ahead matches on:
+// TypeAheadColumn if set, else the sort column, else the first visible
+// column
+func (t *Table) typeAheadColumn() int {
+	if t.TypeAheadColumn >= 0 && t.TypeAheadColumn < len(t.Columns) {
+		return t.TypeAheadColumn
+	}
+	if t.SortColumn >= 0 && t.SortColumn < len(t.Columns) {
+		return t.SortColumn
+	}
+	for _, i := range t.displayOrder() {
+		if !t.Columns[i].Hidden {
+			return i
+		}
+	}
+	return -1
+}
+
+// typeAheadBound returns whether a letter is bound to another action: s,
+// S and r sort, and n and N jump between search matches
+func (t *Table) typeAheadBound(r rune) bool {
+	switch r {
+	case 's', 'S', 'r':
+		return true
+	case 'n', 'N':
+		return t.Search != "" && !t.Filtered()
+	}
+	return false
+}
+
+// handleTypeAhead selects the first row, from the selected one on, whose
+// value in the type-ahead column starts with the characters typed so far.
+// It returns false, ending type-ahead, if no row does.
+func (t *Table) handleTypeAhead(ev *tcell.EventKey) bool {
+	r, ok := t.typeAhead.typeAheadRune(ev, t.typeAheadBound)
+	col := t.typeAheadColumn()
+	if !ok || !t.TypeAhead || col < 0 {
+		t.typeAhead.reset()
+		return false
+	}
+	prefix := t.typeAhead.add(r)
+	idx := findPrefix(t.rowCount(), t.SelectedRow, prefix, func(i int) string {
+		return cellValue(t.displayRow(i), col)
+	})
+	if idx < 0 {
+		t.typeAhead.reset()
+		return false
+	}
+	t.SelectedRow = idx
+	t.scrollToSelected()
+	return true
+}
+
+// typeAheadBound returns whether a letter is bound to another action: n
+// and N cycle through matches while filtered
+func (t *TreeView) typeAheadBound(r rune) bool {
+	return (r == 'n' || r == 'N') && t.Filtered()
+}
+
+// handleTypeAhead selects the first visible node, from the selected one
+// on, whose text starts with the characters typed so far. It returns
+// false, ending type-ahead, if no node does.
+func (t *TreeView) handleTypeAhead(ev *tcell.EventKey) bool {
+	r, ok := t.typeAhead.typeAheadRune(ev, t.typeAheadBound)
+	if !ok || !t.TypeAhead {
+		t.typeAhead.reset()
+		return false
+	}
+	prefix := t.typeAhead.add(r)
+	nodes := t.visibleNodes()
+	start := 0
+	for i, node := range nodes {
+		if node == t.Selected {
+			start = i
+		}
+	}
+	idx := findPrefix(len(nodes), start, prefix, func(i int) string {
+		return nodes[i].Text
+	})
+	if idx < 0 {
+		t.typeAhead.reset()
+		return false
+	}
+	t.Selected = nodes[idx]
+	t.EnsureVisible()
+	return true
+}
+
+// visibleNodes returns the nodes not hidden in collapsed parents, in the
+// order they are drawn
+func (t *TreeView) visibleNodes() []*TreeNode {
+	var nodes []*TreeNode
+	var walk func(node *TreeNode)
+	walk = func(node *TreeNode) {
+		nodes = append(nodes, node)
+		if t.isOpen(node) {
+			for _, child := range t.shownChildren(node) {
+				walk(child)
+			}
+		}
+	}
+	if t.Root != nil {
+		walk(t.Root)
+	}
+	return nodes
+}
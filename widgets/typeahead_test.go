@@ -0,0 +1,134 @@
+package widgets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestTableTypeAhead(t *testing.T) {
+	t.Parallel()
+	table, _ := newProcessTable(t)
+	now := time.Now()
+	table.typeAhead.now = func() time.Time { return now }
+	table.TypeAheadColumn = 2
+
+	// Type-ahead is opt-in, so keys reach the caller by default
+	if table.HandleEvent(tcell.NewEventKey(tcell.KeyRune, 'v', tcell.ModNone)) {
+		t.Error("Expected type-ahead to be off by default")
+	}
+	table.TypeAhead = true
+
+	typeKeys(table, "ngi")
+	if table.SelectedRow != 1 {
+		t.Errorf("Expected ngi to select nginx, got row %d", table.SelectedRow)
+	}
+	typeKeys(table, "nx-")
+	if table.SelectedRow != 2 {
+		t.Errorf("Expected nginx- to select nginx-worker, got row %d", table.SelectedRow)
+	}
+	typeKeys(table, "zzz")
+	if table.SelectedRow != 2 {
+		t.Errorf("Expected no match to keep the selection, got row %d", table.SelectedRow)
+	}
+
+	// After the timeout typing starts a new prefix
+	now = now.Add(2 * typeAheadTimeout)
+	typeKeys(table, "V")
+	if table.SelectedRow != 3 {
+		t.Errorf("Expected V to select vim, got row %d", table.SelectedRow)
+	}
+
+	// Bound letters continue a prefix in progress
+	table.Search = "www" // binds n and N
+	now = now.Add(2 * typeAheadTimeout)
+	typeKeys(table, "in")
+	if table.SelectedRow != 0 {
+		t.Errorf("Expected in to select init, got row %d", table.SelectedRow)
+	}
+	table.Search = ""
+
+	// Keys matching no row reach the caller
+	now = now.Add(2 * typeAheadTimeout)
+	if table.HandleEvent(tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone)) {
+		t.Error("Expected keys matching no row to reach the caller")
+	}
+
+	// Other characters and bound letters don't start a prefix, even if
+	// rows start with them
+	table.Rows[0][2] = "/sbin/init"
+	typeKeys(table, "/")
+	if table.promptMode != promptFilter {
+		t.Error("Expected / to open the filter prompt")
+	}
+	table.HandleEvent(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	typeKeys(table, "s")
+	if table.SortColumn != 0 {
+		t.Errorf("Expected s to sort although sshd starts with it, got column %d", table.SortColumn)
+	}
+
+	// Without a type-ahead column, the sort column is matched
+	table.TypeAheadColumn = -1
+	now = now.Add(2 * typeAheadTimeout)
+	typeKeys(table, "20")
+	if row, _ := table.GetSelectedRow(); row[0] != "200" {
+		t.Errorf("Expected 20 to select PID 200, got %v", row)
+	}
+}
+
+func TestTreeViewTypeAhead(t *testing.T) {
+	t.Parallel()
+	screen := createTestScreen(t)
+	tree := NewTreeView(screen, 0, 0, 20, 10)
+	now := time.Now()
+	tree.typeAhead.now = func() time.Time { return now }
+	tree.TypeAhead = true
+
+	root := tree.AddNode(nil, "/")
+	root.Expanded = true
+	tree.AddNode(root, "etc")
+	usr := tree.AddNode(root, "usr")
+	share := tree.AddNode(usr, "share")
+	tree.AddNode(root, "Users")
+
+	typeKeys := func(text string) {
+		for _, r := range text {
+			tree.HandleKeyEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+		}
+	}
+
+	typeKeys("us")
+	if tree.Selected != usr {
+		t.Errorf("Expected us to select usr, got %q", tree.Selected.Text)
+	}
+	typeKeys("e")
+	if tree.Selected.Text != "Users" {
+		t.Errorf("Expected use to select Users, got %q", tree.Selected.Text)
+	}
+
+	// Nodes in collapsed parents aren't matched
+	now = now.Add(2 * typeAheadTimeout)
+	typeKeys("sh")
+	if tree.Selected == share {
+		t.Error("Expected hidden nodes to be skipped")
+	}
+	usr.Expanded = true
+	now = now.Add(2 * typeAheadTimeout)
+	typeKeys("sh")
+	if tree.Selected != share {
+		t.Errorf("Expected sh to select share, got %q", tree.Selected.Text)
+	}
+
+	// The / binding works although the root starts with it
+	tree.HandleKeyEvent(tcell.NewEventKey(tcell.KeyRune, '/', tcell.ModNone))
+	if !tree.Prompting() {
+		t.Error("Expected / to open the filter prompt")
+	}
+	tree.HandleKeyEvent(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+
+	tree.TypeAhead = false
+	if tree.HandleKeyEvent(tcell.NewEventKey(tcell.KeyRune, 'e', tcell.ModNone)) {
+		t.Error("Expected type-ahead to be off")
+	}
+}